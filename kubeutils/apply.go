@@ -0,0 +1,129 @@
+/*
+ * @Time : 2026/10/17 20:35
+ * @Author : diehao.yuan
+ * @Email : diehao.yuan@outlook.com
+ * @File : apply.go
+ */
+package kubeutils
+
+import (
+	"errors"
+	"fmt"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kerrors "kubeutils/utils/errors"
+	"regexp"
+	"time"
+)
+
+// 默认的field manager，server-side apply时apiserver用它记录字段的所有者
+const DefaultFieldManager = "kubeutils"
+
+// manifestOptions Tools的Create、Update、Apply、Delete的可选配置
+type manifestOptions struct {
+	fieldManager string
+	force        bool
+	dryRun       DryRunMode
+	pruneSetID   string
+	pruneKinds   []schema.GroupVersionKind
+	waitTimeout  time.Duration
+}
+
+// ManifestOption 用于配置Tools的单次操作，例如ApplyWithContext(ctx, yaml, WithFieldManager("ci"), WithForceConflicts())
+type ManifestOption func(*manifestOptions)
+
+// WithFieldManager 设置field manager，不设置时使用Tools.FieldManager，都为空时使用DefaultFieldManager
+func WithFieldManager(fieldManager string) ManifestOption {
+	return func(o *manifestOptions) {
+		o.fieldManager = fieldManager
+	}
+}
+
+// WithForceConflicts Apply时强制接管其他manager管理的字段，和kubectl apply --server-side --force-conflicts一致
+func WithForceConflicts() ManifestOption {
+	return func(o *manifestOptions) {
+		o.force = true
+	}
+}
+
+// WithDryRun 设置dry-run模式，DryRunServer时由apiserver校验(包括准入webhook)但不持久化，
+// DryRunClient时只在本地解析yaml并解析资源类型，不发送写请求
+func WithDryRun(mode DryRunMode) ManifestOption {
+	return func(o *manifestOptions) {
+		o.dryRun = mode
+	}
+}
+
+// newManifestOptions 生成配置，fieldManager依次使用传入的值、Tools.FieldManager和DefaultFieldManager
+func (c *Tools) newManifestOptions(opts []ManifestOption) *manifestOptions {
+	o := &manifestOptions{fieldManager: c.FieldManager}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.fieldManager == "" {
+		o.fieldManager = DefaultFieldManager
+	}
+	return o
+}
+
+// FieldConflict server-side apply时一个字段的所有权冲突
+type FieldConflict struct {
+	// Index 对象所在文档的序号
+	Index     int
+	Kind      string
+	Namespace string
+	Name      string
+	// Field 冲突的字段，例如.spec.replicas
+	Field string
+	// Manager 当前管理该字段的field manager，例如kubectl-client-side-apply
+	Manager string
+	Message string
+}
+
+// ConflictError Apply时有字段被其他manager管理并且没有设置WithForceConflicts时返回，可以通过errors.As获取冲突的字段
+type ConflictError struct {
+	Conflicts []FieldConflict
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("应用失败: %d个字段与其他field manager冲突", len(e.Conflicts))
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == kerrors.ErrConflict
+}
+
+// 冲突信息的格式为：conflict with "kubectl-client-side-apply" using apps/v1
+var conflictManagerPattern = regexp.MustCompile(`conflict with "([^"]*)"`)
+
+// fieldConflicts 从apiserver返回的Conflict错误中解析冲突的字段，不是字段冲突时返回nil
+func fieldConflicts(index int, obj *unstructured.Unstructured, err error) []FieldConflict {
+	if !apierrors.IsConflict(err) {
+		return nil
+	}
+	var statusErr apierrors.APIStatus
+	if !errors.As(err, &statusErr) || statusErr.Status().Details == nil {
+		return nil
+	}
+	var conflicts []FieldConflict
+	for _, cause := range statusErr.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		conflict := FieldConflict{
+			Index:     index,
+			Kind:      obj.GetKind(),
+			Namespace: obj.GetNamespace(),
+			Name:      obj.GetName(),
+			Field:     cause.Field,
+			Message:   cause.Message,
+		}
+		if match := conflictManagerPattern.FindStringSubmatch(cause.Message); match != nil {
+			conflict.Manager = match[1]
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	return conflicts
+}
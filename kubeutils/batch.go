@@ -0,0 +1,97 @@
+/*
+ * @Time : 2026/10/17 16:20
+ * @Author : diehao.yuan
+ * @Email : diehao.yuan@outlook.com
+ * @File : batch.go
+ */
+package kubeutils
+
+import (
+	"context"
+	"fmt"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sort"
+	"sync"
+)
+
+// 批量操作的默认并发数
+const DefaultConcurrency = 5
+
+// BatchResult 批量操作的结果
+type BatchResult struct {
+	Succeeded []string
+	NotFound  []string
+	Forbidden []string
+	// Failed 所有失败的资源及对应的错误，Forbidden的资源也会记录在这里
+	Failed map[string]error
+}
+
+// Err 将失败的资源聚合成一个错误，全部成功或者只有资源不存在时返回nil，
+// 返回的错误支持errors.Is，例如errors.Is(err, context.DeadlineExceeded)
+func (r *BatchResult) Err() error {
+	if len(r.Failed) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(r.Failed))
+	for name := range r.Failed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	errList := make([]error, 0, len(names))
+	for _, name := range names {
+		errList = append(errList, fmt.Errorf("%s: %w", name, r.Failed[name]))
+	}
+	return utilerrors.NewAggregate(errList)
+}
+
+// add 按错误类型记录结果
+func (r *BatchResult) add(name string, err error) {
+	switch {
+	case err == nil:
+		r.Succeeded = append(r.Succeeded, name)
+	case apierrors.IsNotFound(err):
+		r.NotFound = append(r.NotFound, name)
+	case apierrors.IsForbidden(err):
+		r.Forbidden = append(r.Forbidden, name)
+		r.Failed[name] = err
+	default:
+		r.Failed[name] = err
+	}
+}
+
+// runBatch 以指定的并发数对每个名称执行fn，ctx取消后未执行的名称直接记为失败
+func runBatch(ctx context.Context, concurrency int, nameList []string, fn func(name string) error) *BatchResult {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	result := &BatchResult{Failed: make(map[string]error)}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for _, name := range nameList {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			mu.Lock()
+			result.add(name, ctx.Err())
+			mu.Unlock()
+			continue
+		}
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			err := fn(name)
+			mu.Lock()
+			result.add(name, err)
+			mu.Unlock()
+		}(name)
+	}
+	wg.Wait()
+	// 结果按名称排序，方便调用方展示
+	sort.Strings(result.Succeeded)
+	sort.Strings(result.NotFound)
+	sort.Strings(result.Forbidden)
+	return result
+}
@@ -0,0 +1,117 @@
+/*
+ * @Time : 2026/10/17 11:20
+ * @Author : diehao.yuan
+ * @Email : diehao.yuan@outlook.com
+ * @File : cluster.go
+ */
+package kubeutils
+
+import (
+	"errors"
+	"k8s.io/client-go/rest"
+	kerrors "kubeutils/utils/errors"
+	"kubeutils/utils/log"
+	"sync"
+)
+
+// ClusterManager 按集群ID缓存每个集群的ResourceInstance，每个kubeconfig只解析一次，
+// 通过它生成的资源客户端和Tools共用同一组clientset、dynamic client和discovery client
+type ClusterManager struct {
+	mu        sync.RWMutex
+	instances map[string]*ResourceInstance
+}
+
+// New函数用于初始化缓存
+func NewClusterManager() *ClusterManager {
+	return &ClusterManager{
+		instances: make(map[string]*ResourceInstance),
+	}
+}
+
+// Register 注册集群，kubeconfig和缓存中一致时直接返回缓存的实例，不一致时重新生成并替换旧的实例，
+// opts只在生成实例时生效，如果需要修改已注册集群的配置，先调用Evict
+func (m *ClusterManager) Register(clusterId, kubeconfig string, opts ...Option) (*ResourceInstance, error) {
+	m.mu.RLock()
+	instance, ok := m.instances[clusterId]
+	m.mu.RUnlock()
+	if ok && instance.Kubeconfig == kubeconfig {
+		return instance, nil
+	}
+
+	// 在锁外生成客户端，避免阻塞其他集群的读取
+	newInstance, err := NewResourceInstance(kubeconfig, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// 并发注册同一个集群时，以先写入的为准
+	if instance, ok := m.instances[clusterId]; ok && instance.Kubeconfig == kubeconfig {
+		return instance, nil
+	}
+	if ok {
+		log.Infof("ClusterId: %s, kubeconfig changed, replace cached clients!", clusterId)
+	}
+	m.instances[clusterId] = newInstance
+	return newInstance, nil
+}
+
+// RegisterConfig 使用restConfig注册集群，例如集群内运行时使用LoadInClusterConfig的返回值，已注册的同名集群会被替换
+func (m *ClusterManager) RegisterConfig(clusterId string, config *rest.Config, opts ...Option) (*ResourceInstance, error) {
+	instance, err := NewResourceInstanceForConfig(config, opts...)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.instances[clusterId] = instance
+	return instance, nil
+}
+
+// Get 获取缓存的集群实例，集群未注册时返回的错误满足errors.Is(err, ErrNotFound)
+func (m *ClusterManager) Get(clusterId string) (*ResourceInstance, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	instance, ok := m.instances[clusterId]
+	if !ok {
+		return nil, kerrors.New(kerrors.ErrNotFound, errors.New("集群未注册: "+clusterId))
+	}
+	return instance, nil
+}
+
+// Evict 删除缓存的集群实例，kubeconfig变更或者集群下线时调用，已经生成的客户端不受影响
+func (m *ClusterManager) Evict(clusterId string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.instances, clusterId)
+}
+
+// Clusters 返回所有已注册的集群ID
+func (m *ClusterManager) Clusters() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	clusterIds := make([]string, 0, len(m.instances))
+	for clusterId := range m.instances {
+		clusterIds = append(clusterIds, clusterId)
+	}
+	return clusterIds
+}
+
+// Tools 生成指定集群的Tools
+func (m *ClusterManager) Tools(clusterId string) (*Tools, error) {
+	instance, err := m.Get(clusterId)
+	if err != nil {
+		return nil, err
+	}
+	return NewToolsFromInstance(clusterId, instance), nil
+}
+
+// ClusterResource 生成指定集群的资源客户端，例如ClusterResource(manager, "prod", DeploymentKind, item)
+func ClusterResource[T any, L any](m *ClusterManager, clusterId string, kind *Kind[T, L], item *T) (*Resource[T, L], error) {
+	instance, err := m.Get(clusterId)
+	if err != nil {
+		return nil, err
+	}
+	return NewResourceFromInstance(instance, kind, item), nil
+}
@@ -0,0 +1,129 @@
+/*
+ * @Time : 2026/10/17 15:02
+ * @Author : diehao.yuan
+ * @Email : diehao.yuan@outlook.com
+ * @File : config.go
+ */
+package kubeutils
+
+import (
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sort"
+)
+
+// ContextInfo kubeconfig中一个context的信息
+type ContextInfo struct {
+	Name      string
+	Cluster   string
+	User      string
+	Namespace string
+	// Current 为true代表是kubeconfig的current-context
+	Current bool
+}
+
+// restConfigFromKubeconfig 解析kubeconfig内容，contextName为空时使用current-context
+func restConfigFromKubeconfig(kubeconfig, contextName string) (*rest.Config, error) {
+	if contextName == "" {
+		return clientcmd.RESTConfigFromKubeConfig([]byte(kubeconfig))
+	}
+	config, err := clientcmd.Load([]byte(kubeconfig))
+	if err != nil {
+		return nil, err
+	}
+	return clientConfigFor(config, contextName).ClientConfig()
+}
+
+// clientConfigFor 根据kubeconfig和context生成ClientConfig，context不存在时ClientConfig会返回错误
+func clientConfigFor(config *clientcmdapi.Config, contextName string) clientcmd.ClientConfig {
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: contextName}
+	return clientcmd.NewNonInteractiveClientConfig(*config, contextName, overrides, nil)
+}
+
+// LoadKubeconfigFile 从文件中加载kubeconfig，contextName为空时使用current-context，
+// 和kubectl一样，证书等文件的相对路径相对于kubeconfig所在的目录
+func LoadKubeconfigFile(path, contextName string) (*rest.Config, error) {
+	restConfig, err := fileClientConfig(&clientcmd.ClientConfigLoadingRules{ExplicitPath: path}, contextName).ClientConfig()
+	if err != nil {
+		return nil, newClientError(ErrInvalidKubeconfig, err)
+	}
+	return restConfig, nil
+}
+
+// LoadKubeconfigEnv 和kubectl一样加载kubeconfig：$KUBECONFIG中的多个文件会被合并，未设置时使用~/.kube/config
+func LoadKubeconfigEnv(contextName string) (*rest.Config, error) {
+	config, err := clientcmd.NewDefaultClientConfigLoadingRules().Load()
+	if err != nil {
+		return nil, newClientError(ErrInvalidKubeconfig, err)
+	}
+	restConfig, err := clientConfigFor(config, contextName).ClientConfig()
+	if err != nil {
+		return nil, newClientError(ErrInvalidKubeconfig, err)
+	}
+	return restConfig, nil
+}
+
+// LoadInClusterConfig 在Pod中运行时使用ServiceAccount的token和CA访问apiserver
+func LoadInClusterConfig() (*rest.Config, error) {
+	restConfig, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, newClientError(ErrInvalidKubeconfig, err)
+	}
+	return restConfig, nil
+}
+
+// LoadDefaultConfig 优先使用$KUBECONFIG或~/.kube/config，都没有找到时使用集群内配置，
+// 这样同一份代码既可以在本地运行，也可以在Pod中运行
+func LoadDefaultConfig(contextName string) (*rest.Config, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: contextName}
+	// DeferredLoadingClientConfig在没有kubeconfig时会自动使用集群内配置
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides).ClientConfig()
+	if err != nil {
+		return nil, newClientError(ErrInvalidKubeconfig, err)
+	}
+	return restConfig, nil
+}
+
+// ListContexts 列出kubeconfig内容中所有的context，按名称排序
+func ListContexts(kubeconfig string) ([]ContextInfo, error) {
+	config, err := clientcmd.Load([]byte(kubeconfig))
+	if err != nil {
+		return nil, newClientError(ErrInvalidKubeconfig, err)
+	}
+	return contextInfos(config), nil
+}
+
+// ListContextsFromFile 列出kubeconfig文件中所有的context，传入多个文件时和$KUBECONFIG一样合并
+func ListContextsFromFile(paths ...string) ([]ContextInfo, error) {
+	config, err := fileClientConfig(&clientcmd.ClientConfigLoadingRules{Precedence: paths}, "").RawConfig()
+	if err != nil {
+		return nil, newClientError(ErrInvalidKubeconfig, err)
+	}
+	return contextInfos(&config), nil
+}
+
+// fileClientConfig 通过loading rules加载kubeconfig文件，加载时会将文件中的相对路径转换为相对于文件所在目录的路径，
+// clientcmd.LoadFromFile不会做这个转换，相对路径会相对于进程的工作目录
+func fileClientConfig(rules *clientcmd.ClientConfigLoadingRules, contextName string) clientcmd.ClientConfig {
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: contextName}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+}
+
+func contextInfos(config *clientcmdapi.Config) []ContextInfo {
+	contexts := make([]ContextInfo, 0, len(config.Contexts))
+	for name, context := range config.Contexts {
+		contexts = append(contexts, ContextInfo{
+			Name:      name,
+			Cluster:   context.Cluster,
+			User:      context.AuthInfo,
+			Namespace: context.Namespace,
+			Current:   name == config.CurrentContext,
+		})
+	}
+	sort.Slice(contexts, func(i, j int) bool {
+		return contexts[i].Name < contexts[j].Name
+	})
+	return contexts
+}
@@ -0,0 +1,112 @@
+/*
+ * @Time : 2026/10/17 17:05
+ * @Author : diehao.yuan
+ * @Email : diehao.yuan@outlook.com
+ * @File : delete.go
+ */
+package kubeutils
+
+import (
+	"context"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kerrors "kubeutils/utils/errors"
+	"kubeutils/utils/log"
+	"time"
+)
+
+// 等待资源删除时查询的间隔
+const deletionPollInterval = 2 * time.Second
+
+// DeleteOptions 删除单个资源时的配置
+type DeleteOptions struct {
+	// GracePeriodSeconds 为0代表强制删除
+	GracePeriodSeconds *int64
+	// PropagationPolicy 依赖资源的删除策略：Foreground、Background或Orphan，为空时使用资源默认的策略
+	PropagationPolicy metav1.DeletionPropagation
+	// UID 不为空时，只有资源的UID一致才会删除，避免误删同名的新资源
+	UID types.UID
+	// ResourceVersion 不为空时，只有资源没有被修改过才会删除
+	ResourceVersion string
+	// DryRun 为DryRunServer时由apiserver校验但不删除，为DryRunClient时不发送请求
+	DryRun DryRunMode
+	// WaitForDeletion 为true时阻塞直到资源真正被删除，超时由ctx控制，DryRun时不会等待。
+	// Foreground删除时apiserver会在依赖资源删除后才删除该资源，所以同时会等待依赖资源被删除
+	WaitForDeletion bool
+}
+
+// toMetaDeleteOptions 转换为apiserver的DeleteOptions
+func (o DeleteOptions) toMetaDeleteOptions() metav1.DeleteOptions {
+	deleteOptions := metav1.DeleteOptions{
+		GracePeriodSeconds: o.GracePeriodSeconds,
+		DryRun:             o.DryRun.metaDryRun(),
+	}
+	if o.PropagationPolicy != "" {
+		policy := o.PropagationPolicy
+		deleteOptions.PropagationPolicy = &policy
+	}
+	if o.UID != "" || o.ResourceVersion != "" {
+		deleteOptions.Preconditions = &metav1.Preconditions{}
+		if o.UID != "" {
+			uid := o.UID
+			deleteOptions.Preconditions.UID = &uid
+		}
+		if o.ResourceVersion != "" {
+			resourceVersion := o.ResourceVersion
+			deleteOptions.Preconditions.ResourceVersion = &resourceVersion
+		}
+	}
+	return deleteOptions
+}
+
+// DeleteWithOptions 删除资源，支持删除策略、前置条件和等待删除完成，前置条件不满足时返回Conflict错误
+func (c *Resource[T, L]) DeleteWithOptions(ctx context.Context, namespace, name string, opts DeleteOptions) error {
+	log.Warnf("Namespace: %s, Name: %s, DryRun: %s, Delete %s!", namespace, name, opts.DryRun, c.Kind.GroupVersionKind.Kind)
+	if opts.DryRun == DryRunClient {
+		return nil
+	}
+	client := c.client(namespace)
+	if opts.DryRun == DryRunServer {
+		opts.WaitForDeletion = false
+	}
+
+	// 等待删除时需要记录UID，避免把删除后新建的同名资源当成未删除
+	uid := opts.UID
+	if opts.WaitForDeletion && uid == "" {
+		item, err := client.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return kerrors.Classify(err)
+		}
+		if o, ok := any(item).(metav1.Object); ok {
+			uid = o.GetUID()
+		}
+	}
+
+	if err := client.Delete(ctx, name, opts.toMetaDeleteOptions()); err != nil {
+		return kerrors.Classify(err)
+	}
+	if !opts.WaitForDeletion {
+		return nil
+	}
+	return kerrors.Classify(c.waitForDeletion(ctx, namespace, name, uid))
+}
+
+// waitForDeletion 轮询直到资源不存在或者UID发生变化
+func (c *Resource[T, L]) waitForDeletion(ctx context.Context, namespace, name string, uid types.UID) error {
+	client := c.client(namespace)
+	return wait.PollUntilContextCancel(ctx, deletionPollInterval, true, func(ctx context.Context) (bool, error) {
+		item, err := client.Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if o, ok := any(item).(metav1.Object); ok && uid != "" && o.GetUID() != uid {
+			return true, nil
+		}
+		return false, nil
+	})
+}
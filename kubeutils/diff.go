@@ -0,0 +1,251 @@
+/*
+ * @Time : 2026/10/17 21:40
+ * @Author : diehao.yuan
+ * @Email : diehao.yuan@outlook.com
+ * @File : diff.go
+ */
+package kubeutils
+
+import (
+	"context"
+	"fmt"
+	"github.com/pmezard/go-difflib/difflib"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kerrors "kubeutils/utils/errors"
+	"sigs.k8s.io/yaml"
+	"strings"
+)
+
+// DiffAction 应用manifest后对象会发生的变化
+type DiffAction string
+
+const (
+	DiffCreated   DiffAction = "created"
+	DiffChanged   DiffAction = "changed"
+	DiffUnchanged DiffAction = "unchanged"
+	// DiffDeleted 开启WithPrune时将会被清理的对象
+	DiffDeleted DiffAction = "deleted"
+)
+
+// diff时忽略的字段，它们每次写入都会变化或者由apiserver维护，和manifest无关
+var noisyFields = [][]string{
+	{"metadata", "managedFields"},
+	{"metadata", "resourceVersion"},
+	{"metadata", "generation"},
+	{"status"},
+}
+
+// unified diff中每个变更前后保留的行数
+const diffContextLines = 3
+
+// ObjectDiff 一个对象的diff结果
+type ObjectDiff struct {
+	// Index 对象所在文档的序号
+	Index     int
+	Kind      string
+	Namespace string
+	Name      string
+	Action    DiffAction
+	// Diff 集群中的对象和应用后的对象的unified diff，unchanged时为空
+	Diff string
+}
+
+// DiffSummary 各类变化的对象数量
+type DiffSummary struct {
+	Created   int
+	Changed   int
+	Unchanged int
+	Deleted   int
+}
+
+// DiffResult Tools.Diff的结果
+type DiffResult struct {
+	Objects []ObjectDiff
+	Summary DiffSummary
+}
+
+// String 返回所有对象的diff，和kubectl diff的输出类似
+func (r *DiffResult) String() string {
+	var builder strings.Builder
+	for _, object := range r.Objects {
+		builder.WriteString(object.Diff)
+	}
+	return builder.String()
+}
+
+// add 记录一个对象的diff并更新统计
+func (r *DiffResult) add(object ObjectDiff) {
+	r.Objects = append(r.Objects, object)
+	switch object.Action {
+	case DiffCreated:
+		r.Summary.Created++
+	case DiffChanged:
+		r.Summary.Changed++
+	case DiffUnchanged:
+		r.Summary.Unchanged++
+	case DiffDeleted:
+		r.Summary.Deleted++
+	}
+}
+
+// Diff 对比yaml和集群中的对象
+func (c *Tools) Diff(yamlContent string) (result *DiffResult, msg string, err error) {
+	return c.DiffWithContext(context.TODO(), yamlContent)
+}
+
+// DiffWithContext 以server-side dry-run的方式应用yaml中的每个对象，和集群中的对象对比，
+// 返回每个对象的unified diff以及统计，opts和ApplyWithContext一致，解析或者请求失败的项记录在msg中
+func (c *Tools) DiffWithContext(ctx context.Context, yamlContent string, opts ...ManifestOption) (result *DiffResult, msg string, err error) {
+	o := c.newManifestOptions(opts)
+	result = &DiffResult{}
+	if err := o.validatePrune(); err != nil {
+		return result, err.Error(), err
+	}
+	var errMsgList []string
+	var errList []error
+	objects, parseErrList := ParseManifests(yamlContent)
+	for _, parseErr := range parseErrList {
+		errMsgList = append(errMsgList, parseErr.Error())
+		errList = append(errList, parseErr)
+	}
+	sortManifestObjects(objects, false)
+	for _, item := range objects {
+		object, err := c.diffObject(ctx, item.Object, o)
+		if err != nil {
+			errMsgList = append(errMsgList, fmt.Sprintf("第%d项yaml数据对比失败: %s", item.Index, err.Error()))
+			errList = append(errList, err)
+			continue
+		}
+		object.Index = item.Index
+		result.add(object)
+	}
+	// 开启prune时，将会被清理的对象记为deleted，解析失败时无法判断哪些对象需要保留
+	if o.pruneSetID != "" && len(errMsgList) == 0 {
+		applied := make([]*unstructured.Unstructured, 0, len(objects))
+		for _, item := range objects {
+			applied = append(applied, item.Object)
+		}
+		candidates, err := c.pruneCandidates(ctx, o, applied)
+		if err != nil {
+			errMsgList = append(errMsgList, fmt.Sprintf("查找需要清理的对象失败: %s", err.Error()))
+			errList = append(errList, err)
+		}
+		for _, obj := range candidates {
+			liveYAML, err := diffYAML(obj)
+			if err != nil {
+				errMsgList = append(errMsgList, fmt.Sprintf("对比%s %s/%s失败: %s", obj.GetKind(), obj.GetNamespace(), obj.GetName(), err.Error()))
+				continue
+			}
+			result.add(ObjectDiff{
+				Kind:      obj.GetKind(),
+				Namespace: obj.GetNamespace(),
+				Name:      obj.GetName(),
+				Action:    DiffDeleted,
+				Diff:      unifiedDiff(diffPath("live", obj), diffPath("merged", obj), liveYAML, ""),
+			})
+		}
+	}
+	if len(errMsgList) > 0 {
+		return result, strings.Join(errMsgList, "\n"), kerrors.Join("对比失败", errList...)
+	}
+	return result, "", nil
+}
+
+// diffObject 获取集群中的对象，并和dry-run应用后的对象对比
+func (c *Tools) diffObject(ctx context.Context, obj *unstructured.Unstructured, o *manifestOptions) (ObjectDiff, error) {
+	object := ObjectDiff{Kind: obj.GetKind(), Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	obj = obj.DeepCopy()
+	o.markApplySet(obj)
+	dynamicResourceInterface, err := c.resourceInterface(obj)
+	if err != nil {
+		return object, err
+	}
+	live, err := liveObject(ctx, dynamicResourceInterface, obj.GetName())
+	if err != nil {
+		return object, err
+	}
+	merged, err := dynamicResourceInterface.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{
+		FieldManager: o.fieldManager,
+		Force:        o.force,
+		DryRun:       []string{metav1.DryRunAll},
+	})
+	if err != nil {
+		return object, err
+	}
+	object.Namespace = merged.GetNamespace()
+
+	liveYAML, err := diffYAML(live)
+	if err != nil {
+		return object, err
+	}
+	mergedYAML, err := diffYAML(merged)
+	if err != nil {
+		return object, err
+	}
+	switch {
+	case live == nil:
+		object.Action = DiffCreated
+	case liveYAML == mergedYAML:
+		object.Action = DiffUnchanged
+		return object, nil
+	default:
+		object.Action = DiffChanged
+	}
+	object.Diff = unifiedDiff(diffPath("live", merged), diffPath("merged", merged), liveYAML, mergedYAML)
+	return object, nil
+}
+
+// diffPath 生成diff头部的路径，例如live/apps.v1.Deployment.default.nginx
+func diffPath(prefix string, obj *unstructured.Unstructured) string {
+	gvk := obj.GroupVersionKind()
+	parts := []string{prefix + "/" + gvk.GroupVersion().String(), gvk.Kind}
+	if obj.GetNamespace() != "" {
+		parts = append(parts, obj.GetNamespace())
+	}
+	parts = append(parts, obj.GetName())
+	return strings.Join(parts, ".")
+}
+
+// diffYAML 去掉无关字段后转换为yaml，对象为nil时返回空字符串
+func diffYAML(obj *unstructured.Unstructured) (string, error) {
+	if obj == nil {
+		return "", nil
+	}
+	obj = obj.DeepCopy()
+	for _, fields := range noisyFields {
+		unstructured.RemoveNestedField(obj.Object, fields...)
+	}
+	data, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// unifiedDiff 生成两段文本的unified diff，没有差异时返回空字符串。
+// 使用difflib逐行对比，内存占用和行数成正比，上万行的CRD或ConfigMap也可以对比
+func unifiedDiff(fromName, toName, from, to string) string {
+	text, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(from),
+		B:        splitLines(to),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  diffContextLines,
+	})
+	if err != nil {
+		// 只有写入失败时才会返回错误，写入strings.Builder不会失败
+		return ""
+	}
+	return text
+}
+
+// splitLines 按行拆分，每一行都以换行符结尾，difflib输出时不会再添加换行符
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(strings.TrimSuffix(text, "\n"), "\n")
+	lines[len(lines)-1] += "\n"
+	return lines
+}
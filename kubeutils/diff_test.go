@@ -0,0 +1,136 @@
+/*
+ * @Time : 2026/10/18 14:10
+ * @Author : diehao.yuan
+ * @Email : diehao.yuan@outlook.com
+ * @File : diff_test.go
+ */
+package kubeutils
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// numberedLines 生成line1到linen，每行以换行符结尾，changed中的行替换为changed
+func numberedLines(n int, changed ...int) string {
+	replace := map[int]bool{}
+	for _, i := range changed {
+		replace[i] = true
+	}
+	var builder strings.Builder
+	for i := 1; i <= n; i++ {
+		if replace[i] {
+			fmt.Fprintf(&builder, "changed%d\n", i)
+		} else {
+			fmt.Fprintf(&builder, "line%d\n", i)
+		}
+	}
+	return builder.String()
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		want     string
+	}{
+		{
+			name: "相同",
+			from: "a: 1\nb: 2\n",
+			to:   "a: 1\nb: 2\n",
+			want: "",
+		},
+		{
+			name: "结尾换行不同视为相同",
+			from: "a: 1\nb: 2",
+			to:   "a: 1\nb: 2\n",
+			want: "",
+		},
+		{
+			name: "单行",
+			from: "a: 1\n",
+			to:   "a: 2\n",
+			want: "--- live\n+++ merged\n@@ -1 +1 @@\n-a: 1\n+a: 2\n",
+		},
+		{
+			name: "新建",
+			from: "",
+			to:   "a: 1\nb: 2\n",
+			want: "--- live\n+++ merged\n@@ -0,0 +1,2 @@\n+a: 1\n+b: 2\n",
+		},
+		{
+			name: "删除",
+			from: "a: 1\nb: 2\n",
+			to:   "",
+			want: "--- live\n+++ merged\n@@ -1,2 +0,0 @@\n-a: 1\n-b: 2\n",
+		},
+		{
+			name: "前后保留3行上下文",
+			from: numberedLines(10),
+			to:   numberedLines(10, 5),
+			want: "--- live\n+++ merged\n@@ -2,7 +2,7 @@\n line2\n line3\n line4\n-line5\n+changed5\n line6\n line7\n line8\n",
+		},
+		{
+			name: "新增行",
+			from: "a: 1\nc: 3\n",
+			to:   "a: 1\nb: 2\nc: 3\n",
+			want: "--- live\n+++ merged\n@@ -1,2 +1,3 @@\n a: 1\n+b: 2\n c: 3\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("live", "merged", tt.from, tt.to); got != tt.want {
+				t.Errorf("unifiedDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiffHunks(t *testing.T) {
+	tests := []struct {
+		name  string
+		from  string
+		to    string
+		hunks []string
+	}{
+		{
+			name:  "间隔不超过6行时合并为一个hunk",
+			from:  numberedLines(20),
+			to:    numberedLines(20, 2, 8),
+			hunks: []string{"@@ -1,11 +1,11 @@"},
+		},
+		{
+			name:  "间隔超过6行时拆分为多个hunk",
+			from:  numberedLines(20),
+			to:    numberedLines(20, 2, 15),
+			hunks: []string{"@@ -1,5 +1,5 @@", "@@ -12,7 +12,7 @@"},
+		},
+		{
+			name:  "变更在末尾",
+			from:  numberedLines(20),
+			to:    numberedLines(20, 20),
+			hunks: []string{"@@ -17,4 +17,4 @@"},
+		},
+		{
+			name:  "大文件",
+			from:  numberedLines(20000),
+			to:    numberedLines(20000, 10000),
+			hunks: []string{"@@ -9997,7 +9997,7 @@"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hunks []string
+			for _, line := range strings.Split(unifiedDiff("live", "merged", tt.from, tt.to), "\n") {
+				if strings.HasPrefix(line, "@@") {
+					hunks = append(hunks, line)
+				}
+			}
+			if !reflect.DeepEqual(hunks, tt.hunks) {
+				t.Errorf("hunks = %v, want %v", hunks, tt.hunks)
+			}
+		})
+	}
+}
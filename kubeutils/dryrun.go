@@ -0,0 +1,86 @@
+/*
+ * @Time : 2026/10/17 21:10
+ * @Author : diehao.yuan
+ * @Email : diehao.yuan@outlook.com
+ * @File : dryrun.go
+ */
+package kubeutils
+
+import (
+	"context"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "kubeutils/utils/errors"
+	"kubeutils/utils/log"
+)
+
+// DryRunMode dry-run模式
+type DryRunMode string
+
+const (
+	// DryRunNone 正常执行，默认值
+	DryRunNone DryRunMode = ""
+	// DryRunServer 请求发送到apiserver，经过校验和准入控制(包括webhook)，但是不会持久化
+	DryRunServer DryRunMode = "server"
+	// DryRunClient 不访问apiserver，只在本地解析和处理，返回将要提交的对象
+	DryRunClient DryRunMode = "client"
+)
+
+// metaDryRun 转换为apiserver请求中的dryRun参数
+func (m DryRunMode) metaDryRun() []string {
+	if m == DryRunServer {
+		return []string{metav1.DryRunAll}
+	}
+	return nil
+}
+
+// WriteOptions 创建和更新资源时的配置
+type WriteOptions struct {
+	// DryRun 为DryRunServer或DryRunClient时不会持久化
+	DryRun DryRunMode
+	// FieldManager 为空时由apiserver根据User-Agent生成
+	FieldManager string
+}
+
+// CreateWithOptions 创建资源并返回apiserver持久化后的对象，DryRun时返回apiserver将会持久化的对象
+func (c *Resource[T, L]) CreateWithOptions(ctx context.Context, namespace string, opts WriteOptions) (*T, error) {
+	log.Infof("Namespace: %s, Name: %s, DryRun: %s, Create %s!", namespace, c.itemName(), opts.DryRun, c.Kind.GroupVersionKind.Kind)
+	if err := c.checkItem(); err != nil {
+		return nil, err
+	}
+	if opts.DryRun == DryRunClient {
+		return c.clientDryRunItem(namespace), nil
+	}
+	item, err := c.client(namespace).Create(ctx, c.Item, metav1.CreateOptions{DryRun: opts.DryRun.metaDryRun(), FieldManager: opts.FieldManager})
+	if err != nil {
+		return nil, kerrors.Classify(err)
+	}
+	c.setTypeMeta(item)
+	return item, nil
+}
+
+// UpdateWithOptions 更新资源并返回apiserver持久化后的对象，DryRun时返回apiserver将会持久化的对象
+func (c *Resource[T, L]) UpdateWithOptions(ctx context.Context, namespace string, opts WriteOptions) (*T, error) {
+	log.Warnf("Namespace: %s, Name: %s, DryRun: %s, Update %s!", namespace, c.itemName(), opts.DryRun, c.Kind.GroupVersionKind.Kind)
+	if err := c.checkItem(); err != nil {
+		return nil, err
+	}
+	if opts.DryRun == DryRunClient {
+		return c.clientDryRunItem(namespace), nil
+	}
+	item, err := c.client(namespace).Update(ctx, c.Item, metav1.UpdateOptions{DryRun: opts.DryRun.metaDryRun(), FieldManager: opts.FieldManager})
+	if err != nil {
+		return nil, kerrors.Classify(err)
+	}
+	c.setTypeMeta(item)
+	return item, nil
+}
+
+// clientDryRunItem 返回Item的副本，并补充TypeMeta和namespace
+func (c *Resource[T, L]) clientDryRunItem(namespace string) *T {
+	item := c.deepCopy(c.Item)
+	c.setTypeMeta(item)
+	if o, ok := any(item).(metav1.Object); ok && c.Kind.Namespaced && o.GetNamespace() == "" {
+		o.SetNamespace(namespace)
+	}
+	return item
+}
@@ -0,0 +1,58 @@
+/*
+ * @Time : 2026/10/17 13:05
+ * @Author : diehao.yuan
+ * @Email : diehao.yuan@outlook.com
+ * @File : errors.go
+ */
+package kubeutils
+
+import (
+	"errors"
+	"k8s.io/client-go/rest"
+	kerrors "kubeutils/utils/errors"
+	"net/url"
+	"strings"
+)
+
+// 生成客户端时可能出现的错误类型，可以通过errors.Is判断，例如errors.Is(err, ErrInvalidKubeconfig)，
+// 和kubeutils/utils/errors中的错误相同，其他错误类型见该包
+var (
+	// kubeconfig格式错误、缺少字段或者证书无法解析
+	ErrInvalidKubeconfig = kerrors.ErrInvalidKubeconfig
+	// exec或auth-provider认证插件无法初始化或获取凭证失败
+	ErrAuthProvider = kerrors.ErrAuthProvider
+	// 无法连接到apiserver，例如DNS解析失败、连接被拒绝或者超时
+	ErrConnection = kerrors.ErrConnection
+)
+
+// ClientError 生成客户端或者检查集群连通性时返回的错误，Reason为上面定义的错误类型之一
+type ClientError = kerrors.Error
+
+// newClientError 生成ClientError
+func newClientError(reason, err error) error {
+	return kerrors.New(reason, err)
+}
+
+// classifyConfigError 对根据restConfig生成客户端时的错误进行分类，
+// 这个阶段不会访问apiserver，配置了认证插件时认为是认证插件的问题，否则是kubeconfig本身的问题
+func classifyConfigError(config *rest.Config, err error) error {
+	if config != nil && (config.ExecProvider != nil || config.AuthProvider != nil) {
+		return newClientError(ErrAuthProvider, err)
+	}
+	return newClientError(ErrInvalidKubeconfig, err)
+}
+
+// classifyRequestError 对访问apiserver时的错误进行分类，apiserver已经返回的错误原样返回
+func classifyRequestError(config *rest.Config, err error) error {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return err
+	}
+	// exec插件获取凭证失败时，错误信息中会包含getting credentials
+	if config != nil && (config.ExecProvider != nil || config.AuthProvider != nil) &&
+		strings.Contains(urlErr.Err.Error(), "getting credentials") {
+		return newClientError(ErrAuthProvider, err)
+	}
+	// 其余url.Error都是请求没有到达apiserver，例如DNS解析失败、连接被拒绝、TLS握手失败或者超时
+	return newClientError(ErrConnection, err)
+}
@@ -0,0 +1,192 @@
+/*
+ * @Time : 2026/10/17 10:40
+ * @Author : diehao.yuan
+ * @Email : diehao.yuan@outlook.com
+ * @File : kinds.go
+ */
+package kubeutils
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// 注册资源类型，新增资源类型时在这里添加一行即可，然后通过NewResource(kubeconfig, XxxKind, item)使用
+var (
+	ConfigMapKind = NewNamespacedKind(func(cs kubernetes.Interface, ns string) ResourceClient[corev1.ConfigMap, corev1.ConfigMapList] {
+		return cs.CoreV1().ConfigMaps(ns)
+	})
+	SecretKind = NewNamespacedKind(func(cs kubernetes.Interface, ns string) ResourceClient[corev1.Secret, corev1.SecretList] {
+		return cs.CoreV1().Secrets(ns)
+	})
+	ServiceKind = withoutDeleteCollection(NewNamespacedKind(func(cs kubernetes.Interface, ns string) ResourceClient[corev1.Service, corev1.ServiceList] {
+		return cs.CoreV1().Services(ns)
+	}))
+	PodKind = NewNamespacedKind(func(cs kubernetes.Interface, ns string) ResourceClient[corev1.Pod, corev1.PodList] {
+		return cs.CoreV1().Pods(ns)
+	})
+	PersistentVolumeClaimKind = NewNamespacedKind(func(cs kubernetes.Interface, ns string) ResourceClient[corev1.PersistentVolumeClaim, corev1.PersistentVolumeClaimList] {
+		return cs.CoreV1().PersistentVolumeClaims(ns)
+	})
+	PersistentVolumeKind = NewClusterKind(func(cs kubernetes.Interface) ResourceClient[corev1.PersistentVolume, corev1.PersistentVolumeList] {
+		return cs.CoreV1().PersistentVolumes()
+	})
+	NamespaceKind = withoutDeleteCollection(NewClusterKind(func(cs kubernetes.Interface) ResourceClient[corev1.Namespace, corev1.NamespaceList] {
+		return cs.CoreV1().Namespaces()
+	}))
+	NodeKind = NewClusterKind(func(cs kubernetes.Interface) ResourceClient[corev1.Node, corev1.NodeList] {
+		return cs.CoreV1().Nodes()
+	})
+	DeploymentKind = NewNamespacedKind(func(cs kubernetes.Interface, ns string) ResourceClient[appsv1.Deployment, appsv1.DeploymentList] {
+		return cs.AppsV1().Deployments(ns)
+	})
+	StatefulSetKind = NewNamespacedKind(func(cs kubernetes.Interface, ns string) ResourceClient[appsv1.StatefulSet, appsv1.StatefulSetList] {
+		return cs.AppsV1().StatefulSets(ns)
+	})
+	DaemonSetKind = NewNamespacedKind(func(cs kubernetes.Interface, ns string) ResourceClient[appsv1.DaemonSet, appsv1.DaemonSetList] {
+		return cs.AppsV1().DaemonSets(ns)
+	})
+	ReplicaSetKind = NewNamespacedKind(func(cs kubernetes.Interface, ns string) ResourceClient[appsv1.ReplicaSet, appsv1.ReplicaSetList] {
+		return cs.AppsV1().ReplicaSets(ns)
+	})
+	CronJobKind = NewNamespacedKind(func(cs kubernetes.Interface, ns string) ResourceClient[batchv1.CronJob, batchv1.CronJobList] {
+		return cs.BatchV1().CronJobs(ns)
+	})
+	IngressKind = NewNamespacedKind(func(cs kubernetes.Interface, ns string) ResourceClient[networkingv1.Ingress, networkingv1.IngressList] {
+		return cs.NetworkingV1().Ingresses(ns)
+	})
+	IngressClassKind = NewClusterKind(func(cs kubernetes.Interface) ResourceClient[networkingv1.IngressClass, networkingv1.IngressClassList] {
+		return cs.NetworkingV1().IngressClasses()
+	})
+	RoleKind = NewNamespacedKind(func(cs kubernetes.Interface, ns string) ResourceClient[rbacv1.Role, rbacv1.RoleList] {
+		return cs.RbacV1().Roles(ns)
+	})
+	RoleBindingKind = NewNamespacedKind(func(cs kubernetes.Interface, ns string) ResourceClient[rbacv1.RoleBinding, rbacv1.RoleBindingList] {
+		return cs.RbacV1().RoleBindings(ns)
+	})
+	ClusterRoleKind = NewClusterKind(func(cs kubernetes.Interface) ResourceClient[rbacv1.ClusterRole, rbacv1.ClusterRoleList] {
+		return cs.RbacV1().ClusterRoles()
+	})
+	ClusterRoleBindingKind = NewClusterKind(func(cs kubernetes.Interface) ResourceClient[rbacv1.ClusterRoleBinding, rbacv1.ClusterRoleBindingList] {
+		return cs.RbacV1().ClusterRoleBindings()
+	})
+	StorageClassKind = NewClusterKind(func(cs kubernetes.Interface) ResourceClient[storagev1.StorageClass, storagev1.StorageClassList] {
+		return cs.StorageV1().StorageClasses()
+	})
+)
+
+// 以下类型和New函数用于兼容之前每种资源一个文件的写法
+type (
+	ConfigMap             = Resource[corev1.ConfigMap, corev1.ConfigMapList]
+	Secret                = Resource[corev1.Secret, corev1.SecretList]
+	Service               = Resource[corev1.Service, corev1.ServiceList]
+	Pod                   = Resource[corev1.Pod, corev1.PodList]
+	PersistentVolumeClaim = Resource[corev1.PersistentVolumeClaim, corev1.PersistentVolumeClaimList]
+	PersistentVolume      = Resource[corev1.PersistentVolume, corev1.PersistentVolumeList]
+	Namespace             = Resource[corev1.Namespace, corev1.NamespaceList]
+	Node                  = Resource[corev1.Node, corev1.NodeList]
+	Deployment            = Resource[appsv1.Deployment, appsv1.DeploymentList]
+	StatefulSet           = Resource[appsv1.StatefulSet, appsv1.StatefulSetList]
+	DaemonSet             = Resource[appsv1.DaemonSet, appsv1.DaemonSetList]
+	ReplicaSet            = Resource[appsv1.ReplicaSet, appsv1.ReplicaSetList]
+	CronJob               = Resource[batchv1.CronJob, batchv1.CronJobList]
+	Ingress               = Resource[networkingv1.Ingress, networkingv1.IngressList]
+	IngressClass          = Resource[networkingv1.IngressClass, networkingv1.IngressClassList]
+	Role                  = Resource[rbacv1.Role, rbacv1.RoleList]
+	RoleBinding           = Resource[rbacv1.RoleBinding, rbacv1.RoleBindingList]
+	ClusterRole           = Resource[rbacv1.ClusterRole, rbacv1.ClusterRoleList]
+	ClusterRoleBinding    = Resource[rbacv1.ClusterRoleBinding, rbacv1.ClusterRoleBindingList]
+	StorageClass          = Resource[storagev1.StorageClass, storagev1.StorageClassList]
+)
+
+var _ TypedKubeUtilser[appsv1.Deployment] = (*Deployment)(nil)
+
+func NewConfigMap(kubeconfig string, item *corev1.ConfigMap, opts ...Option) (*ConfigMap, error) {
+	return NewResource(kubeconfig, ConfigMapKind, item, opts...)
+}
+
+func NewSecret(kubeconfig string, item *corev1.Secret, opts ...Option) (*Secret, error) {
+	return NewResource(kubeconfig, SecretKind, item, opts...)
+}
+
+func NewService(kubeconfig string, item *corev1.Service, opts ...Option) (*Service, error) {
+	return NewResource(kubeconfig, ServiceKind, item, opts...)
+}
+
+func NewPod(kubeconfig string, item *corev1.Pod, opts ...Option) (*Pod, error) {
+	return NewResource(kubeconfig, PodKind, item, opts...)
+}
+
+func NewPersistentVolumeClaim(kubeconfig string, item *corev1.PersistentVolumeClaim, opts ...Option) (*PersistentVolumeClaim, error) {
+	return NewResource(kubeconfig, PersistentVolumeClaimKind, item, opts...)
+}
+
+func NewPersistentVolume(kubeconfig string, item *corev1.PersistentVolume, opts ...Option) (*PersistentVolume, error) {
+	return NewResource(kubeconfig, PersistentVolumeKind, item, opts...)
+}
+
+func NewNamespace(kubeconfig string, item *corev1.Namespace, opts ...Option) (*Namespace, error) {
+	return NewResource(kubeconfig, NamespaceKind, item, opts...)
+}
+
+func NewNode(kubeconfig string, item *corev1.Node, opts ...Option) (*Node, error) {
+	return NewResource(kubeconfig, NodeKind, item, opts...)
+}
+
+func NewDeployment(kubeconfig string, item *appsv1.Deployment, opts ...Option) (*Deployment, error) {
+	return NewResource(kubeconfig, DeploymentKind, item, opts...)
+}
+
+func NewStatefulSet(kubeconfig string, item *appsv1.StatefulSet, opts ...Option) (*StatefulSet, error) {
+	return NewResource(kubeconfig, StatefulSetKind, item, opts...)
+}
+
+func NewDaemonSet(kubeconfig string, item *appsv1.DaemonSet, opts ...Option) (*DaemonSet, error) {
+	return NewResource(kubeconfig, DaemonSetKind, item, opts...)
+}
+
+func NewReplicaSet(kubeconfig string, item *appsv1.ReplicaSet, opts ...Option) (*ReplicaSet, error) {
+	return NewResource(kubeconfig, ReplicaSetKind, item, opts...)
+}
+
+func NewCronJob(kubeconfig string, item *batchv1.CronJob, opts ...Option) (*CronJob, error) {
+	return NewResource(kubeconfig, CronJobKind, item, opts...)
+}
+
+func NewIngress(kubeconfig string, item *networkingv1.Ingress, opts ...Option) (*Ingress, error) {
+	return NewResource(kubeconfig, IngressKind, item, opts...)
+}
+
+func NewIngressClass(kubeconfig string, item *networkingv1.IngressClass, opts ...Option) (*IngressClass, error) {
+	return NewResource(kubeconfig, IngressClassKind, item, opts...)
+}
+
+func NewRole(kubeconfig string, item *rbacv1.Role, opts ...Option) (*Role, error) {
+	return NewResource(kubeconfig, RoleKind, item, opts...)
+}
+
+func NewRoleBinding(kubeconfig string, item *rbacv1.RoleBinding, opts ...Option) (*RoleBinding, error) {
+	return NewResource(kubeconfig, RoleBindingKind, item, opts...)
+}
+
+func NewClusterRole(kubeconfig string, item *rbacv1.ClusterRole, opts ...Option) (*ClusterRole, error) {
+	return NewResource(kubeconfig, ClusterRoleKind, item, opts...)
+}
+
+func NewClusterRoleBinding(kubeconfig string, item *rbacv1.ClusterRoleBinding, opts ...Option) (*ClusterRoleBinding, error) {
+	return NewResource(kubeconfig, ClusterRoleBindingKind, item, opts...)
+}
+
+func NewStorageClass(kubeconfig string, item *storagev1.StorageClass, opts ...Option) (*StorageClass, error) {
+	return NewResource(kubeconfig, StorageClassKind, item, opts...)
+}
+
+// Deprecated: 拼写错误，请使用NewIngress
+func NewIngerss(kubeconfig string, item *networkingv1.Ingress, opts ...Option) (*Ingress, error) {
+	return NewIngress(kubeconfig, item, opts...)
+}
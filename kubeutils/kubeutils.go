@@ -7,9 +7,13 @@
 package kubeutils
 
 import (
+	"context"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/clientcmd"
-	"time"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
 )
 
 // 定义kubeutils接口
@@ -26,29 +30,105 @@ type KubeUtilser interface {
 	List(string, string, string) (interface{}, error)
 	// namespace name
 	Get(string, string) (interface{}, error)
+
+	// 以下方法与上面一一对应，第一个参数为ctx，用于取消请求或设置超时
+	CreateWithContext(context.Context, string) error
+	DeleteWithContext(context.Context, string, string, *int64) error
+	DeleteListWithContext(context.Context, string, []string, *int64) error
+	UpdateWithContext(context.Context, string) error
+	ListWithContext(context.Context, string, string, string) (interface{}, error)
+	GetWithContext(context.Context, string, string) (interface{}, error)
 }
 
+// ResourceInstance 保存一个集群的客户端，clientset、dynamic client和discovery client共用同一个HTTP连接
 type ResourceInstance struct {
-	Kubeconfig string
-	Clientset  *kubernetes.Clientset
+	Kubeconfig      string
+	RestConfig      *rest.Config
+	Clientset       *kubernetes.Clientset
+	DynamicClient   *dynamic.DynamicClient
+	DiscoveryClient discovery.CachedDiscoveryInterface
+	RESTMapper      *restmapper.DeferredDiscoveryRESTMapper
+	// WatchClientset 和Clientset相同但是没有请求超时，用于watch等长连接，否则连接会在超时后被断开
+	WatchClientset *kubernetes.Clientset
+}
+
+// NewResourceInstance 解析kubeconfig并生成客户端，kubeconfig错误时返回ErrInvalidKubeconfig或ErrAuthProvider
+func NewResourceInstance(kubeconfig string, opts ...Option) (*ResourceInstance, error) {
+	instance := &ResourceInstance{}
+	if err := instance.Init(kubeconfig, opts...); err != nil {
+		return nil, err
+	}
+	return instance, nil
+}
+
+// NewResourceInstanceForConfig 使用已有的restConfig生成客户端，例如LoadInClusterConfig或LoadKubeconfigFile的返回值
+func NewResourceInstanceForConfig(config *rest.Config, opts ...Option) (*ResourceInstance, error) {
+	instance := &ResourceInstance{}
+	// 复制一份，避免修改调用方的配置
+	if err := instance.initForConfig(rest.CopyConfig(config), newClientOptions(opts)); err != nil {
+		return nil, err
+	}
+	return instance, nil
 }
 
-func (c *ResourceInstance) Init(kubeconfig string) {
+// Init 解析kubeconfig并生成客户端，失败时返回错误，不会panic
+func (c *ResourceInstance) Init(kubeconfig string, opts ...Option) error {
 	c.Kubeconfig = kubeconfig
+	o := newClientOptions(opts)
 
-	// 生成Clientset
-	restConfig, err := clientcmd.RESTConfigFromKubeConfig([]byte(c.Kubeconfig))
+	// 生成restConfig，默认使用kubeconfig中的current-context
+	restConfig, err := restConfigFromKubeconfig(kubeconfig, o.contextName)
 	if err != nil {
-		msg := "解析kubeconfig错误: " + err.Error()
-		panic(msg)
+		return newClientError(ErrInvalidKubeconfig, err)
+	}
+	return c.initForConfig(restConfig, o)
+}
+
+func (c *ResourceInstance) initForConfig(restConfig *rest.Config, o *clientOptions) error {
+	// 设置超时时间、限流等配置
+	if err := o.applyTo(restConfig); err != nil {
+		return newClientError(ErrInvalidKubeconfig, err)
 	}
+	c.RestConfig = restConfig
 
-	// 设置超时时间
-	restConfig.Timeout = 15 * time.Second
-	clientSet, err := kubernetes.NewForConfig(restConfig)
+	// 所有客户端共用一个httpClient，避免每个客户端单独建立连接
+	httpClient, err := rest.HTTPClientFor(restConfig)
+	if err != nil {
+		return classifyConfigError(restConfig, err)
+	}
+	clientSet, err := kubernetes.NewForConfigAndClient(restConfig, httpClient)
 	if err != nil {
-		msg := "创建clientset失败: " + err.Error()
-		panic(msg)
+		return classifyConfigError(restConfig, err)
 	}
 	c.Clientset = clientSet
+
+	// http.Client的Timeout对整个响应生效，watch需要单独的httpClient，TLS连接仍然共用
+	watchConfig := rest.CopyConfig(restConfig)
+	watchConfig.Timeout = 0
+	watchClientSet, err := kubernetes.NewForConfig(watchConfig)
+	if err != nil {
+		return classifyConfigError(restConfig, err)
+	}
+	c.WatchClientset = watchClientSet
+
+	dynamicClient, err := dynamic.NewForConfigAndClient(restConfig, httpClient)
+	if err != nil {
+		return classifyConfigError(restConfig, err)
+	}
+	c.DynamicClient = dynamicClient
+
+	// discovery的结果缓存在内存中，供后续解析资源类型使用
+	c.DiscoveryClient = memory.NewMemCacheClient(clientSet.Discovery())
+	// RESTMapper在第一次使用时才会访问discovery接口
+	c.RESTMapper = restmapper.NewDeferredDiscoveryRESTMapper(c.DiscoveryClient)
+	return nil
+}
+
+// Ping 访问apiserver检查集群是否可以连通，连接失败时返回ErrConnection，认证插件失败时返回ErrAuthProvider
+func (c *ResourceInstance) Ping(ctx context.Context) error {
+	_, err := c.Clientset.Discovery().RESTClient().Get().AbsPath("/version").Do(ctx).Raw()
+	if err != nil {
+		return classifyRequestError(c.RestConfig, err)
+	}
+	return nil
 }
@@ -0,0 +1,122 @@
+/*
+ * @Time : 2026/10/18 10:50
+ * @Author : diehao.yuan
+ * @Email : diehao.yuan@outlook.com
+ * @File : list.go
+ */
+package kubeutils
+
+import (
+	"context"
+	"iter"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "kubeutils/utils/errors"
+	"kubeutils/utils/log"
+)
+
+// DefaultPageSize 分页查询时每页的数量，和kubectl一致
+const DefaultPageSize = 500
+
+// ListOptions 查询资源列表时的配置
+type ListOptions struct {
+	LabelSelector string
+	FieldSelector string
+	// Limit 每页的数量，为0时使用DefaultPageSize，小于0时不分页，一次查询全部
+	Limit int64
+	// FromCache 为true时设置ResourceVersion="0"，由apiserver的watch cache返回，不访问etcd，
+	// 速度更快但是数据可能略有延迟，旧版本的apiserver此时会忽略Limit一次返回全部
+	FromCache bool
+}
+
+// toMetaListOptions 转换为apiserver的ListOptions
+func (o ListOptions) toMetaListOptions() metav1.ListOptions {
+	listOptions := metav1.ListOptions{
+		LabelSelector: o.LabelSelector,
+		FieldSelector: o.FieldSelector,
+	}
+	switch {
+	case o.Limit == 0:
+		listOptions.Limit = DefaultPageSize
+	case o.Limit > 0:
+		listOptions.Limit = o.Limit
+	}
+	if o.FromCache {
+		listOptions.ResourceVersion = "0"
+	}
+	return listOptions
+}
+
+// ListWithOptions 分页查询资源列表并返回全部结果，continue token过期时重新一次查询全部
+func (c *Resource[T, L]) ListWithOptions(ctx context.Context, namespace string, opts ListOptions) ([]T, error) {
+	log.Infof("Namespace: %s, Get %s List!", namespace, c.Kind.GroupVersionKind.Kind)
+	var items []T
+	err := c.listPages(ctx, namespace, opts.toMetaListOptions(), func(page []T, _ string) bool {
+		items = append(items, page...)
+		return true
+	})
+	if apierrors.IsResourceExpired(err) {
+		// 分页期间数据变化太多导致continue token过期，和client-go的pager一样退化为一次查询全部
+		opts.Limit = -1
+		items = nil
+		err = c.listPages(ctx, namespace, opts.toMetaListOptions(), func(page []T, _ string) bool {
+			items = append(items, page...)
+			return true
+		})
+	}
+	if err != nil {
+		return nil, kerrors.Classify(err)
+	}
+	if items == nil {
+		items = []T{}
+	}
+	return items, nil
+}
+
+// Iterate 分页查询资源列表，逐个返回资源，不会一次把全部资源放在内存中，适合资源很多的情况。
+// 查询失败时返回(nil, err)并结束，break会停止查询后续的页
+func (c *Resource[T, L]) Iterate(ctx context.Context, namespace string, opts ListOptions) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		log.Infof("Namespace: %s, Iterate %s List!", namespace, c.Kind.GroupVersionKind.Kind)
+		err := c.listPages(ctx, namespace, opts.toMetaListOptions(), func(page []T, _ string) bool {
+			for i := range page {
+				if !yield(&page[i], nil) {
+					return false
+				}
+			}
+			return true
+		})
+		if err != nil {
+			yield(nil, kerrors.Classify(err))
+		}
+	}
+}
+
+// listPages 依次查询每一页，fn返回false时停止，resourceVersion为列表的resourceVersion
+func (c *Resource[T, L]) listPages(ctx context.Context, namespace string, listOptions metav1.ListOptions, fn func(page []T, resourceVersion string) bool) error {
+	client := c.client(namespace)
+	for {
+		list, err := client.List(ctx, listOptions)
+		if err != nil {
+			return err
+		}
+		items, err := c.listItems(list)
+		if err != nil {
+			return err
+		}
+		listMeta, err := meta.ListAccessor(list)
+		if err != nil {
+			return err
+		}
+		if !fn(items, listMeta.GetResourceVersion()) {
+			return nil
+		}
+		if listMeta.GetContinue() == "" {
+			return nil
+		}
+		// 使用continue token时不能再指定ResourceVersion
+		listOptions.Continue = listMeta.GetContinue()
+		listOptions.ResourceVersion = ""
+	}
+}
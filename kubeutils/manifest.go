@@ -0,0 +1,235 @@
+/*
+ * @Time : 2026/10/17 18:30
+ * @Author : diehao.yuan
+ * @Email : diehao.yuan@outlook.com
+ * @File : manifest.go
+ */
+package kubeutils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	kerrors "kubeutils/utils/errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ManifestObject 从yaml或json中解析出来的一个对象
+type ManifestObject struct {
+	// Index 对象所在文档的序号，从1开始，List中的多个对象序号相同
+	Index int
+	// Line 对象所在文档在原始内容中的起始行号，从1开始
+	Line   int
+	Object *unstructured.Unstructured
+}
+
+// ManifestError 解析某一项文档失败时的错误
+type ManifestError struct {
+	Index int
+	Line  int
+	Err   error
+}
+
+func (e *ManifestError) Error() string {
+	return fmt.Sprintf("第%d项yaml数据(第%d行)解析失败: %s", e.Index, e.Line, e.Err.Error())
+}
+
+func (e *ManifestError) Unwrap() error {
+	return e.Err
+}
+
+// Is 解析失败的错误类型都是ErrInvalidManifest
+func (e *ManifestError) Is(target error) bool {
+	return target == kerrors.ErrInvalidManifest
+}
+
+// manifestDocument 拆分后的一项文档
+type manifestDocument struct {
+	index int
+	line  int
+	data  []byte
+}
+
+// yaml解析错误中的行号，需要换算成原始内容中的行号
+var yamlLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// ParseManifests 解析yaml流或json，支持以---分隔的多个yaml文档、多个连续的json对象、json数组，
+// 以及v1/List、DeploymentList这类List对象(会被展开为其中的items)。
+// 只有注释或者空白的文档会被忽略，某一项解析失败不影响其他项，失败的项以ManifestError返回
+func ParseManifests(content string) ([]ManifestObject, []error) {
+	var documents []manifestDocument
+	var errList []error
+	trimmed := strings.TrimSpace(content)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		documents, errList = splitJSONDocuments(content)
+	} else {
+		documents = splitYAMLDocuments(content)
+	}
+
+	var objects []ManifestObject
+	for _, document := range documents {
+		items, err := decodeManifestDocument(document.data)
+		if err != nil {
+			errList = append(errList, &ManifestError{Index: document.index, Line: document.line, Err: offsetYAMLError(err, document.line)})
+			continue
+		}
+		for _, item := range items {
+			objects = append(objects, ManifestObject{Index: document.index, Line: document.line, Object: item})
+		}
+	}
+	return objects, errList
+}
+
+// splitYAMLDocuments 按行拆分yaml流，只有顶格的---才是文档分隔符，块字符串中缩进的---不受影响
+func splitYAMLDocuments(content string) []manifestDocument {
+	var documents []manifestDocument
+	var buf bytes.Buffer
+	index, startLine, lineNo := 1, 1, 0
+	flush := func() {
+		if !isEmptyYAMLDocument(buf.String()) {
+			documents = append(documents, manifestDocument{index: index, line: startLine, data: append([]byte(nil), buf.Bytes()...)})
+			index++
+		}
+		buf.Reset()
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if rest, ok := splitYAMLSeparator(line); ok {
+			flush()
+			startLine = lineNo + 1
+			// "--- apiVersion: v1"、"--- !!map"中---之后的内容属于新文档的第一行
+			if rest != "" {
+				startLine = lineNo
+				buf.WriteString(rest)
+				buf.WriteByte('\n')
+			}
+			continue
+		}
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	flush()
+	return documents
+}
+
+// splitYAMLSeparator 判断是否为文档分隔符，例如"---"、"--- # comment"、"--- apiVersion: v1"，
+// ---之后必须是空白或者行尾，返回---之后的内容，注释视为没有内容
+func splitYAMLSeparator(line string) (string, bool) {
+	if !strings.HasPrefix(line, "---") {
+		return "", false
+	}
+	rest := line[3:]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' && rest[0] != '\r' {
+		return "", false
+	}
+	rest = strings.TrimSpace(rest)
+	if strings.HasPrefix(rest, "#") {
+		return "", true
+	}
+	return rest, true
+}
+
+// isEmptyYAMLDocument 判断文档是否只包含注释或者空白
+func isEmptyYAMLDocument(document string) bool {
+	for _, line := range strings.Split(document, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") && line != "..." {
+			return false
+		}
+	}
+	return true
+}
+
+// splitJSONDocuments 拆分连续的json对象或者json数组
+func splitJSONDocuments(content string) ([]manifestDocument, []error) {
+	var documents []manifestDocument
+	decoder := json.NewDecoder(strings.NewReader(content))
+	index := 1
+	for {
+		offset := decoder.InputOffset()
+		var raw json.RawMessage
+		err := decoder.Decode(&raw)
+		if err == io.EOF {
+			break
+		}
+		line := lineAt(content, offset)
+		if err != nil {
+			// json格式错误时无法继续拆分后面的内容
+			return documents, []error{&ManifestError{Index: index, Line: line, Err: err}}
+		}
+		raw = bytes.TrimSpace(raw)
+		if bytes.HasPrefix(raw, []byte("[")) {
+			var elements []json.RawMessage
+			if err := json.Unmarshal(raw, &elements); err != nil {
+				return documents, []error{&ManifestError{Index: index, Line: line, Err: err}}
+			}
+			for _, element := range elements {
+				documents = append(documents, manifestDocument{index: index, line: line, data: element})
+				index++
+			}
+			continue
+		}
+		documents = append(documents, manifestDocument{index: index, line: line, data: raw})
+		index++
+	}
+	return documents, nil
+}
+
+// lineAt 计算偏移量所在的行号，跳过偏移量之后的空白
+func lineAt(content string, offset int64) int {
+	i := int(offset)
+	for i < len(content) && strings.ContainsRune(" \t\r\n", rune(content[i])) {
+		i++
+	}
+	return strings.Count(content[:i], "\n") + 1
+}
+
+// decodeManifestDocument 将一项文档解析为对象，List对象会被展开
+func decodeManifestDocument(data []byte) ([]*unstructured.Unstructured, error) {
+	jsonData, err := utilyaml.ToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	if string(bytes.TrimSpace(jsonData)) == "null" {
+		return nil, nil
+	}
+	obj, _, err := unstructured.UnstructuredJSONScheme.Decode(jsonData, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch o := obj.(type) {
+	case *unstructured.Unstructured:
+		return []*unstructured.Unstructured{o}, nil
+	case *unstructured.UnstructuredList:
+		items := make([]*unstructured.Unstructured, 0, len(o.Items))
+		for i := range o.Items {
+			items = append(items, &o.Items[i])
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("不支持的对象类型: %T", obj)
+}
+
+// offsetYAMLError 将yaml错误中文档内的行号换算为原始内容中的行号
+func offsetYAMLError(err error, startLine int) error {
+	msg := err.Error()
+	if !strings.Contains(msg, "yaml: line ") {
+		return err
+	}
+	msg = yamlLinePattern.ReplaceAllStringFunc(msg, func(match string) string {
+		n, _ := strconv.Atoi(strings.TrimPrefix(match, "line "))
+		return "line " + strconv.Itoa(n+startLine-1)
+	})
+	return errors.New(msg)
+}
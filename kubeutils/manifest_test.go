@@ -0,0 +1,157 @@
+/*
+ * @Time : 2026/10/18 14:40
+ * @Author : diehao.yuan
+ * @Email : diehao.yuan@outlook.com
+ * @File : manifest_test.go
+ */
+package kubeutils
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	kerrors "kubeutils/utils/errors"
+)
+
+// parsedObject 用于对比解析结果，只保留序号、行号、kind和name
+type parsedObject struct {
+	Index int
+	Line  int
+	Kind  string
+	Name  string
+}
+
+func TestParseManifests(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []parsedObject
+	}{
+		{
+			name:    "单个文档",
+			content: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n",
+			want:    []parsedObject{{1, 1, "ConfigMap", "a"}},
+		},
+		{
+			name:    "以---分隔",
+			content: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n---\napiVersion: v1\nkind: Secret\nmetadata:\n  name: b\n",
+			want:    []parsedObject{{1, 1, "ConfigMap", "a"}, {2, 6, "Secret", "b"}},
+		},
+		{
+			name:    "开头的---和带注释的---",
+			content: "---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n--- # next\napiVersion: v1\nkind: Secret\nmetadata:\n  name: b\n",
+			want:    []parsedObject{{1, 2, "ConfigMap", "a"}, {2, 7, "Secret", "b"}},
+		},
+		{
+			name:    "---后面有内容",
+			content: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n--- apiVersion: v1\nkind: Secret\nmetadata:\n  name: b\n",
+			want:    []parsedObject{{1, 1, "ConfigMap", "a"}, {2, 5, "Secret", "b"}},
+		},
+		{
+			name:    "---后面是tag",
+			content: "--- !!map\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n",
+			want:    []parsedObject{{1, 1, "ConfigMap", "a"}},
+		},
+		{
+			name:    "块字符串中缩进的---不是分隔符",
+			content: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\ndata:\n  doc: |\n    ---\n    x: 1\n",
+			want:    []parsedObject{{1, 1, "ConfigMap", "a"}},
+		},
+		{
+			name:    "只有注释或者空白的文档被忽略",
+			content: "# header\n---\n\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n---\n# trailing\n",
+			want:    []parsedObject{{1, 5, "ConfigMap", "a"}},
+		},
+		{
+			name:    "连续的json对象",
+			content: "{\"apiVersion\":\"v1\",\"kind\":\"ConfigMap\",\"metadata\":{\"name\":\"a\"}}\n\n{\"apiVersion\":\"v1\",\"kind\":\"Secret\",\"metadata\":{\"name\":\"b\"}}\n",
+			want:    []parsedObject{{1, 1, "ConfigMap", "a"}, {2, 3, "Secret", "b"}},
+		},
+		{
+			name:    "json数组",
+			content: "[\n{\"apiVersion\":\"v1\",\"kind\":\"ConfigMap\",\"metadata\":{\"name\":\"a\"}},\n{\"apiVersion\":\"v1\",\"kind\":\"Secret\",\"metadata\":{\"name\":\"b\"}}\n]\n",
+			want:    []parsedObject{{1, 1, "ConfigMap", "a"}, {2, 1, "Secret", "b"}},
+		},
+		{
+			name:    "List展开后序号相同",
+			content: "apiVersion: v1\nkind: List\nitems:\n- apiVersion: v1\n  kind: ConfigMap\n  metadata:\n    name: a\n- apiVersion: v1\n  kind: Secret\n  metadata:\n    name: b\n---\napiVersion: v1\nkind: Service\nmetadata:\n  name: c\n",
+			want:    []parsedObject{{1, 1, "ConfigMap", "a"}, {1, 1, "Secret", "b"}, {2, 13, "Service", "c"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects, errList := ParseManifests(tt.content)
+			if len(errList) > 0 {
+				t.Fatalf("ParseManifests() errors = %v", errList)
+			}
+			var got []parsedObject
+			for _, object := range objects {
+				got = append(got, parsedObject{object.Index, object.Line, object.Object.GetKind(), object.Object.GetName()})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseManifests() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseManifestsErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantCount int
+		wantIndex int
+		wantLine  int
+		// wantMsg 错误信息中应当包含的内容，例如换算后的行号
+		wantMsg string
+	}{
+		{
+			name:      "yaml错误的行号换算为原始内容中的行号",
+			content:   "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n---\napiVersion: v1\nkind: [\n",
+			wantCount: 1,
+			wantIndex: 2,
+			wantLine:  6,
+			wantMsg:   "line 7",
+		},
+		{
+			name:      "缺少kind",
+			content:   "apiVersion: v1\nmetadata:\n  name: a\n---\napiVersion: v1\nkind: Secret\nmetadata:\n  name: b\n",
+			wantCount: 1,
+			wantIndex: 1,
+			wantLine:  1,
+		},
+		{
+			name:      "json格式错误",
+			content:   "{\"apiVersion\":\"v1\",\"kind\":\"ConfigMap\",\"metadata\":{\"name\":\"a\"}}\n{\"apiVersion\":",
+			wantCount: 1,
+			wantIndex: 2,
+			wantLine:  2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects, errList := ParseManifests(tt.content)
+			if len(objects) != tt.wantCount {
+				t.Errorf("ParseManifests() objects = %d, want %d", len(objects), tt.wantCount)
+			}
+			if len(errList) != 1 {
+				t.Fatalf("ParseManifests() errors = %v, want 1 error", errList)
+			}
+			var manifestErr *ManifestError
+			if !errors.As(errList[0], &manifestErr) {
+				t.Fatalf("error %T is not *ManifestError", errList[0])
+			}
+			if manifestErr.Index != tt.wantIndex || manifestErr.Line != tt.wantLine {
+				t.Errorf("ManifestError index = %d, line = %d, want %d, %d", manifestErr.Index, manifestErr.Line, tt.wantIndex, tt.wantLine)
+			}
+			if !errors.Is(errList[0], kerrors.ErrInvalidManifest) {
+				t.Errorf("errors.Is(err, ErrInvalidManifest) = false")
+			}
+			if !strings.Contains(errList[0].Error(), tt.wantMsg) {
+				t.Errorf("error %q does not contain %q", errList[0].Error(), tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+/*
+ * @Time : 2026/10/18 09:50
+ * @Author : diehao.yuan
+ * @Email : diehao.yuan@outlook.com
+ * @File : mutate.go
+ */
+package kubeutils
+
+import (
+	"context"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	kerrors "kubeutils/utils/errors"
+	"kubeutils/utils/log"
+)
+
+// Mutate 获取资源并修改后更新，冲突时自动重试
+func (c *Resource[T, L]) Mutate(namespace, name string, mutate func(obj *T) error) (*T, error) {
+	return c.MutateWithContext(context.TODO(), namespace, name, mutate)
+}
+
+// MutateWithContext 获取集群中最新的资源，调用mutate修改后更新，返回更新后的对象。
+// 其他客户端同时修改导致Conflict时，重新获取并再次调用mutate，因此mutate可能被调用多次，应当只根据obj进行修改；
+// 重试间隔由Resource.Backoff控制。mutate返回错误时不会更新也不会重试，mutate没有修改对象时不发送更新请求
+func (c *Resource[T, L]) MutateWithContext(ctx context.Context, namespace, name string, mutate func(obj *T) error) (*T, error) {
+	log.Warnf("Namespace: %s, Name: %s, Mutate %s!", namespace, name, c.Kind.GroupVersionKind.Kind)
+	client := c.client(namespace)
+	var result *T
+	err := retry.RetryOnConflict(c.backoff(), func() error {
+		live, err := client.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		obj := c.deepCopy(live)
+		if err := mutate(obj); err != nil {
+			return err
+		}
+		if equality.Semantic.DeepEqual(live, obj) {
+			result = live
+			return nil
+		}
+		result, err = client.Update(ctx, obj, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, kerrors.Classify(err)
+	}
+	c.setTypeMeta(result)
+	return result, nil
+}
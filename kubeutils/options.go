@@ -0,0 +1,134 @@
+/*
+ * @Time : 2026/10/17 14:10
+ * @Author : diehao.yuan
+ * @Email : diehao.yuan@outlook.com
+ * @File : options.go
+ */
+package kubeutils
+
+import (
+	"errors"
+	"k8s.io/client-go/rest"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// 默认的请求超时时间
+const DefaultTimeout = 15 * time.Second
+
+// clientOptions 保存生成客户端时的可选配置
+type clientOptions struct {
+	timeout     time.Duration
+	qps         float32
+	burst       int
+	userAgent   string
+	proxyURL    string
+	insecure    bool
+	impersonate *rest.ImpersonationConfig
+	contextName string
+}
+
+// Option 用于配置客户端，所有New函数都可以传入，例如NewDeployment(kubeconfig, item, WithTimeout(time.Minute))
+type Option func(*clientOptions)
+
+// WithTimeout 设置单个请求的超时时间，默认15秒，0代表不超时
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithQPS 设置客户端限流的QPS，不设置时使用client-go的默认值5
+func WithQPS(qps float32) Option {
+	return func(o *clientOptions) {
+		o.qps = qps
+	}
+}
+
+// WithBurst 设置客户端限流的突发请求数，不设置时使用client-go的默认值10
+func WithBurst(burst int) Option {
+	return func(o *clientOptions) {
+		o.burst = burst
+	}
+}
+
+// WithUserAgent 设置请求的User-Agent，便于在apiserver审计日志中区分调用方
+func WithUserAgent(userAgent string) Option {
+	return func(o *clientOptions) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithProxyURL 通过代理访问apiserver，例如http://127.0.0.1:8080
+func WithProxyURL(proxyURL string) Option {
+	return func(o *clientOptions) {
+		o.proxyURL = proxyURL
+	}
+}
+
+// WithInsecureSkipTLS 跳过apiserver证书校验，只建议在测试环境中使用
+func WithInsecureSkipTLS() Option {
+	return func(o *clientOptions) {
+		o.insecure = true
+	}
+}
+
+// WithImpersonation 以指定的用户和用户组身份访问apiserver，需要当前凭证拥有impersonate权限
+func WithImpersonation(userName string, groups ...string) Option {
+	return func(o *clientOptions) {
+		o.impersonate = &rest.ImpersonationConfig{
+			UserName: userName,
+			Groups:   groups,
+		}
+	}
+}
+
+// WithKubeContext 使用kubeconfig中指定的context，不设置时使用current-context
+func WithKubeContext(contextName string) Option {
+	return func(o *clientOptions) {
+		o.contextName = contextName
+	}
+}
+
+// newClientOptions 生成默认配置，然后依次应用传入的Option
+func newClientOptions(opts []Option) *clientOptions {
+	o := &clientOptions{
+		timeout: DefaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// applyTo 将配置写入restConfig
+func (o *clientOptions) applyTo(config *rest.Config) error {
+	config.Timeout = o.timeout
+	if o.qps > 0 {
+		config.QPS = o.qps
+	}
+	if o.burst > 0 {
+		config.Burst = o.burst
+	}
+	if o.userAgent != "" {
+		config.UserAgent = o.userAgent
+	}
+	if o.proxyURL != "" {
+		proxy, err := url.Parse(o.proxyURL)
+		if err != nil || proxy.Scheme == "" || proxy.Host == "" {
+			return errors.New("代理地址不合法: " + o.proxyURL)
+		}
+		config.Proxy = http.ProxyURL(proxy)
+	}
+	if o.insecure {
+		// 跳过证书校验时不能同时指定CA，否则client-go会报错
+		config.Insecure = true
+		config.CAData = nil
+		config.CAFile = ""
+	}
+	if o.impersonate != nil {
+		config.Impersonate = *o.impersonate
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+/*
+ * @Time : 2026/10/17 19:40
+ * @Author : diehao.yuan
+ * @Email : diehao.yuan@outlook.com
+ * @File : order.go
+ */
+package kubeutils
+
+import (
+	"context"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"kubeutils/utils/log"
+	"sort"
+	"time"
+)
+
+// 创建时按以下顺序处理，被依赖的资源在前，删除时顺序相反，未列出的类型(例如自定义资源)排在最后
+var kindOrder = []string{
+	"Namespace",
+	"CustomResourceDefinition",
+	"PriorityClass",
+	"StorageClass",
+	"ServiceAccount",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"Role",
+	"RoleBinding",
+	"ResourceQuota",
+	"LimitRange",
+	"NetworkPolicy",
+	"PodDisruptionBudget",
+	"Secret",
+	"ConfigMap",
+	"PersistentVolume",
+	"PersistentVolumeClaim",
+	"Service",
+	"DaemonSet",
+	"Pod",
+	"ReplicationController",
+	"ReplicaSet",
+	"Deployment",
+	"HorizontalPodAutoscaler",
+	"StatefulSet",
+	"Job",
+	"CronJob",
+	"IngressClass",
+	"Ingress",
+	"APIService",
+	"MutatingWebhookConfiguration",
+	"ValidatingWebhookConfiguration",
+}
+
+var kindPriority = func() map[string]int {
+	priority := make(map[string]int, len(kindOrder))
+	for i, kind := range kindOrder {
+		priority[kind] = i
+	}
+	return priority
+}()
+
+// CRD的GVR，用于等待CRD可用
+var crdResource = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+const (
+	// 等待CRD变为Established的最长时间
+	crdEstablishTimeout = time.Minute
+	crdPollInterval     = time.Second
+)
+
+// priorityOf 返回资源类型的排序优先级，数值越小越先创建
+func priorityOf(obj *unstructured.Unstructured) int {
+	if priority, ok := kindPriority[obj.GetKind()]; ok {
+		return priority
+	}
+	return len(kindOrder)
+}
+
+// isCRD 判断是否为CRD
+func isCRD(obj *unstructured.Unstructured) bool {
+	return obj.GetKind() == "CustomResourceDefinition" && obj.GroupVersionKind().Group == crdResource.Group
+}
+
+// sortManifestObjects 按依赖关系排序，相同类型保持文件中的顺序，reverse为true时用于删除
+func sortManifestObjects(objects []ManifestObject, reverse bool) {
+	sort.SliceStable(objects, func(i, j int) bool {
+		pi, pj := priorityOf(objects[i].Object), priorityOf(objects[j].Object)
+		if reverse {
+			return pi > pj
+		}
+		return pi < pj
+	})
+}
+
+// waitForCRDsEstablished 等待CRD变为Established，之后才能创建对应的自定义资源，返回没有变为Established的CRD及其错误。
+// CRD暂时查询不到时继续等待，其他错误例如没有权限时直接返回，不会一直等到超时
+func (c *Tools) waitForCRDsEstablished(ctx context.Context, names []string) map[string]error {
+	ctx, cancel := context.WithTimeout(ctx, crdEstablishTimeout)
+	defer cancel()
+	var failed map[string]error
+	for _, name := range names {
+		log.Infof("Name: %s, Wait CustomResourceDefinition Established!", name)
+		err := wait.PollUntilContextCancel(ctx, crdPollInterval, true, func(ctx context.Context) (bool, error) {
+			crd, err := c.DynamicClient.Resource(crdResource).Get(ctx, name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			if err != nil {
+				return false, err
+			}
+			return hasCondition(crd, "Established", "True"), nil
+		})
+		if err != nil {
+			if failed == nil {
+				failed = map[string]error{}
+			}
+			failed[name] = err
+		}
+	}
+	// CRD可用后清空discovery缓存，否则RESTMapper无法解析新的资源类型
+	c.RESTMapper.Reset()
+	return failed
+}
+
+// hasCondition 判断status.conditions中是否有指定类型和状态的condition
+func hasCondition(obj *unstructured.Unstructured, conditionType, status string) bool {
+	condition := findCondition(obj, conditionType)
+	return condition != nil && condition["status"] == status
+}
@@ -0,0 +1,229 @@
+/*
+ * @Time : 2026/10/18 10:20
+ * @Author : diehao.yuan
+ * @Email : diehao.yuan@outlook.com
+ * @File : patch.go
+ */
+package kubeutils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	kerrors "kubeutils/utils/errors"
+	"kubeutils/utils/log"
+	"strconv"
+)
+
+// Patch 使用json patch、merge patch、strategic merge patch或apply patch修改资源
+func (c *Resource[T, L]) Patch(namespace, name string, patchType types.PatchType, data []byte) (*T, error) {
+	return c.PatchWithContext(context.TODO(), namespace, name, patchType, data)
+}
+
+// PatchWithContext 修改资源并返回修改后的对象，ctx用于取消请求或设置超时，
+// patchType为types.ApplyPatchType时使用DefaultFieldManager作为field manager
+func (c *Resource[T, L]) PatchWithContext(ctx context.Context, namespace, name string, patchType types.PatchType, data []byte) (*T, error) {
+	log.Warnf("Namespace: %s, Name: %s, Patch %s with %s!", namespace, name, c.Kind.GroupVersionKind.Kind, patchType)
+	item, err := c.client(namespace).Patch(ctx, name, patchType, data, patchOptions(patchType, ""))
+	if err != nil {
+		return nil, kerrors.Classify(err)
+	}
+	c.setTypeMeta(item)
+	return item, nil
+}
+
+// SetLabels 添加或修改label，不影响其他label
+func (c *Resource[T, L]) SetLabels(namespace, name string, labels map[string]string) (*T, error) {
+	return c.SetLabelsWithContext(context.TODO(), namespace, name, labels)
+}
+
+// SetLabelsWithContext 添加或修改label，ctx用于取消请求或设置超时
+func (c *Resource[T, L]) SetLabelsWithContext(ctx context.Context, namespace, name string, labels map[string]string) (*T, error) {
+	return c.patchMetadata(ctx, namespace, name, "labels", stringMapPatch(labels))
+}
+
+// RemoveLabels 删除label，不存在的label会被忽略
+func (c *Resource[T, L]) RemoveLabels(namespace, name string, keys ...string) (*T, error) {
+	return c.RemoveLabelsWithContext(context.TODO(), namespace, name, keys...)
+}
+
+// RemoveLabelsWithContext 删除label，ctx用于取消请求或设置超时
+func (c *Resource[T, L]) RemoveLabelsWithContext(ctx context.Context, namespace, name string, keys ...string) (*T, error) {
+	return c.patchMetadata(ctx, namespace, name, "labels", removeKeysPatch(keys))
+}
+
+// SetAnnotations 添加或修改annotation，不影响其他annotation
+func (c *Resource[T, L]) SetAnnotations(namespace, name string, annotations map[string]string) (*T, error) {
+	return c.SetAnnotationsWithContext(context.TODO(), namespace, name, annotations)
+}
+
+// SetAnnotationsWithContext 添加或修改annotation，ctx用于取消请求或设置超时
+func (c *Resource[T, L]) SetAnnotationsWithContext(ctx context.Context, namespace, name string, annotations map[string]string) (*T, error) {
+	return c.patchMetadata(ctx, namespace, name, "annotations", stringMapPatch(annotations))
+}
+
+// RemoveAnnotations 删除annotation，不存在的annotation会被忽略
+func (c *Resource[T, L]) RemoveAnnotations(namespace, name string, keys ...string) (*T, error) {
+	return c.RemoveAnnotationsWithContext(context.TODO(), namespace, name, keys...)
+}
+
+// RemoveAnnotationsWithContext 删除annotation，ctx用于取消请求或设置超时
+func (c *Resource[T, L]) RemoveAnnotationsWithContext(ctx context.Context, namespace, name string, keys ...string) (*T, error) {
+	return c.patchMetadata(ctx, namespace, name, "annotations", removeKeysPatch(keys))
+}
+
+// RemoveFinalizers 删除指定的finalizer，不指定时删除全部finalizer，常用于清理卡在Terminating的资源
+func (c *Resource[T, L]) RemoveFinalizers(namespace, name string, finalizers ...string) (*T, error) {
+	return c.RemoveFinalizersWithContext(context.TODO(), namespace, name, finalizers...)
+}
+
+// RemoveFinalizersWithContext 删除指定的finalizer，ctx用于取消请求或设置超时
+func (c *Resource[T, L]) RemoveFinalizersWithContext(ctx context.Context, namespace, name string, finalizers ...string) (*T, error) {
+	patchType, data, err := removeFinalizersPatch(c.Kind.GroupVersionKind, finalizers)
+	if err != nil {
+		return nil, err
+	}
+	return c.PatchWithContext(ctx, namespace, name, patchType, data)
+}
+
+// patchMetadata 修改metadata中的一个map字段，merge patch对所有资源类型都适用
+func (c *Resource[T, L]) patchMetadata(ctx context.Context, namespace, name, field string, value map[string]interface{}) (*T, error) {
+	data, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{field: value},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.PatchWithContext(ctx, namespace, name, types.MergePatchType, data)
+}
+
+func stringMapPatch(values map[string]string) map[string]interface{} {
+	patch := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		patch[key] = value
+	}
+	return patch
+}
+
+// removeKeysPatch merge patch中值为null表示删除
+func removeKeysPatch(keys []string) map[string]interface{} {
+	patch := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		patch[key] = nil
+	}
+	return patch
+}
+
+// removeFinalizersPatch 生成删除finalizer的patch。删除全部时使用merge patch；
+// 删除部分时内置资源使用strategic merge patch的$deleteFromPrimitiveList，
+// 自定义资源不支持strategic merge patch，只能使用json patch按下标删除，因此需要先获取对象，见Tools.RemoveFinalizersWithContext
+func removeFinalizersPatch(gvk schema.GroupVersionKind, finalizers []string) (types.PatchType, []byte, error) {
+	if len(finalizers) == 0 {
+		data, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{"finalizers": nil},
+		})
+		return types.MergePatchType, data, err
+	}
+	if !scheme.Scheme.Recognizes(gvk) {
+		return "", nil, kerrors.New(kerrors.ErrInvalidManifest, fmt.Errorf("%s不支持strategic merge patch，无法只删除部分finalizer", gvk.Kind))
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"$deleteFromPrimitiveList/finalizers": finalizers},
+	})
+	return types.StrategicMergePatchType, data, err
+}
+
+// patchOptions 生成PatchOptions，apply patch必须指定field manager
+func patchOptions(patchType types.PatchType, fieldManager string) metav1.PatchOptions {
+	if patchType != types.ApplyPatchType {
+		return metav1.PatchOptions{FieldManager: fieldManager}
+	}
+	if fieldManager == "" {
+		fieldManager = DefaultFieldManager
+	}
+	return metav1.PatchOptions{FieldManager: fieldManager}
+}
+
+// Patch 修改任意资源，包括自定义资源，apiVersion和kind例如apps/v1和Deployment，集群级别的资源namespace为空
+func (c *Tools) Patch(apiVersion, kind, namespace, name string, patchType types.PatchType, data []byte) (*unstructured.Unstructured, error) {
+	return c.PatchWithContext(context.TODO(), apiVersion, kind, namespace, name, patchType, data)
+}
+
+// PatchWithContext 修改任意资源并返回修改后的对象，ctx用于取消请求或设置超时，field manager使用Tools.FieldManager。
+// 自定义资源不支持strategic merge patch，需要使用json patch或merge patch
+func (c *Tools) PatchWithContext(ctx context.Context, apiVersion, kind, namespace, name string, patchType types.PatchType, data []byte) (*unstructured.Unstructured, error) {
+	log.Warnf("Namespace: %s, Name: %s, Patch %s with %s!", namespace, name, kind, patchType)
+	dynamicResourceInterface, err := c.resourceInterface(objectRef(apiVersion, kind, namespace))
+	if err != nil {
+		return nil, kerrors.Classify(err)
+	}
+	result, err := dynamicResourceInterface.Patch(ctx, name, patchType, data, patchOptions(patchType, c.FieldManager))
+	if err != nil {
+		return nil, kerrors.Classify(err)
+	}
+	return result, nil
+}
+
+// objectRef 生成只有apiVersion、kind和namespace的对象，用于解析资源类型
+func objectRef(apiVersion, kind, namespace string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	return obj
+}
+
+// RemoveFinalizers 删除任意资源的finalizer，不指定时删除全部
+func (c *Tools) RemoveFinalizers(apiVersion, kind, namespace, name string, finalizers ...string) (*unstructured.Unstructured, error) {
+	return c.RemoveFinalizersWithContext(context.TODO(), apiVersion, kind, namespace, name, finalizers...)
+}
+
+// RemoveFinalizersWithContext 删除任意资源的finalizer，ctx用于取消请求或设置超时。
+// 删除部分finalizer时使用带test的json patch，对象在获取之后被修改会返回错误而不是误删
+func (c *Tools) RemoveFinalizersWithContext(ctx context.Context, apiVersion, kind, namespace, name string, finalizers ...string) (*unstructured.Unstructured, error) {
+	if len(finalizers) == 0 {
+		patchType, data, err := removeFinalizersPatch(schema.FromAPIVersionAndKind(apiVersion, kind), nil)
+		if err != nil {
+			return nil, err
+		}
+		return c.PatchWithContext(ctx, apiVersion, kind, namespace, name, patchType, data)
+	}
+	dynamicResourceInterface, err := c.resourceInterface(objectRef(apiVersion, kind, namespace))
+	if err != nil {
+		return nil, kerrors.Classify(err)
+	}
+	live, err := dynamicResourceInterface.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, kerrors.Classify(err)
+	}
+	remove := make(map[string]bool, len(finalizers))
+	for _, finalizer := range finalizers {
+		remove[finalizer] = true
+	}
+	// 从后往前删除，前面的下标不受影响
+	current := live.GetFinalizers()
+	var ops []map[string]interface{}
+	for i := len(current) - 1; i >= 0; i-- {
+		if !remove[current[i]] {
+			continue
+		}
+		path := "/metadata/finalizers/" + strconv.Itoa(i)
+		ops = append(ops,
+			map[string]interface{}{"op": "test", "path": path, "value": current[i]},
+			map[string]interface{}{"op": "remove", "path": path},
+		)
+	}
+	if len(ops) == 0 {
+		return live, nil
+	}
+	data, err := json.Marshal(ops)
+	if err != nil {
+		return nil, err
+	}
+	return c.PatchWithContext(ctx, apiVersion, kind, namespace, name, types.JSONPatchType, data)
+}
@@ -0,0 +1,197 @@
+/*
+ * @Time : 2026/10/17 22:10
+ * @Author : diehao.yuan
+ * @Email : diehao.yuan@outlook.com
+ * @File : prune.go
+ */
+package kubeutils
+
+import (
+	"context"
+	"fmt"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"kubeutils/utils/log"
+	"strings"
+)
+
+const (
+	// ApplySetLabel 标记对象属于哪个manifest集合，值为set id。
+	// 不使用kubectl ApplySet的applyset.kubernetes.io/part-of，它要求特定格式的id和parent对象，混用会被kubectl误判
+	ApplySetLabel = "kubeutils.pengfuji.io/apply-set"
+	// ApplySetAnnotation 记录对象由哪个field manager应用到集合中
+	ApplySetAnnotation = "kubeutils.pengfuji.io/apply-set-manager"
+)
+
+// DefaultPruneKinds 默认允许prune的资源类型，和kubectl apply --prune的默认列表一致，
+// 但是不包括Namespace和PersistentVolume，误删它们的代价太大，需要时通过WithPrune显式指定；
+// 也不包括Endpoints，它由endpoints controller根据Service生成，会复制Service的label
+var DefaultPruneKinds = []schema.GroupVersionKind{
+	{Group: "", Version: "v1", Kind: "ConfigMap"},
+	{Group: "", Version: "v1", Kind: "PersistentVolumeClaim"},
+	{Group: "", Version: "v1", Kind: "Pod"},
+	{Group: "", Version: "v1", Kind: "ReplicationController"},
+	{Group: "", Version: "v1", Kind: "Secret"},
+	{Group: "", Version: "v1", Kind: "Service"},
+	{Group: "batch", Version: "v1", Kind: "Job"},
+	{Group: "batch", Version: "v1", Kind: "CronJob"},
+	{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"},
+	{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+	{Group: "apps", Version: "v1", Kind: "Deployment"},
+	{Group: "apps", Version: "v1", Kind: "ReplicaSet"},
+	{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+}
+
+// WithPrune Apply时给对象打上ApplySetLabel，并删除之前以相同setID应用、但是不在本次yaml中的对象。
+// kinds为允许删除的资源类型，为空时使用DefaultPruneKinds，和WithDryRun一起使用时只预览不删除。
+// 查找旧对象时会在所有namespace中按label查询，需要对应资源的集群级list权限
+func WithPrune(setID string, kinds ...schema.GroupVersionKind) ManifestOption {
+	return func(o *manifestOptions) {
+		o.pruneSetID = setID
+		o.pruneKinds = kinds
+	}
+}
+
+// validatePrune 检查set id是否可以作为label的值
+func (o *manifestOptions) validatePrune() error {
+	if o.pruneSetID == "" {
+		return nil
+	}
+	if errs := validation.IsValidLabelValue(o.pruneSetID); len(errs) > 0 {
+		return fmt.Errorf("set id %q不合法: %s", o.pruneSetID, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// markApplySet 给对象打上set id，未开启prune时不做处理
+func (o *manifestOptions) markApplySet(obj *unstructured.Unstructured) {
+	if o.pruneSetID == "" {
+		return
+	}
+	objLabels := obj.GetLabels()
+	if objLabels == nil {
+		objLabels = map[string]string{}
+	}
+	objLabels[ApplySetLabel] = o.pruneSetID
+	obj.SetLabels(objLabels)
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[ApplySetAnnotation] = o.fieldManager
+	obj.SetAnnotations(annotations)
+}
+
+// pruneKey 用于判断集群中的对象是否仍然在yaml中
+type pruneKey struct {
+	group     string
+	kind      string
+	namespace string
+	name      string
+}
+
+// objectKey 生成对象的pruneKey，namespace为空的namespaced资源和resourceInterface一样视为default
+func (c *Tools) objectKey(obj *unstructured.Unstructured) (pruneKey, error) {
+	gvk := obj.GroupVersionKind()
+	mapping, err := c.restMapping(gvk)
+	if err != nil {
+		return pruneKey{}, err
+	}
+	key := pruneKey{group: gvk.Group, kind: gvk.Kind, name: obj.GetName()}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		key.namespace = obj.GetNamespace()
+		if key.namespace == "" {
+			key.namespace = "default"
+		}
+	}
+	return key, nil
+}
+
+// pruneCandidates 查找属于set id、但是不在applied中的对象，集群中不存在的资源类型会被跳过。
+// 只有带ApplySetAnnotation的对象才是通过Apply创建的，控制器从其他对象复制label生成的对象，
+// 例如Service对应的Endpoints，只有ApplySetLabel，不会被删除
+func (c *Tools) pruneCandidates(ctx context.Context, o *manifestOptions, applied []*unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	keep := map[pruneKey]bool{}
+	for _, obj := range applied {
+		key, err := c.objectKey(obj)
+		if err != nil {
+			return nil, err
+		}
+		keep[key] = true
+	}
+	kinds := o.pruneKinds
+	if len(kinds) == 0 {
+		kinds = DefaultPruneKinds
+	}
+	selector := labels.Set{ApplySetLabel: o.pruneSetID}.String()
+	var candidates []*unstructured.Unstructured
+	for _, gvk := range kinds {
+		mapping, err := c.restMapping(gvk)
+		if meta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		// 不指定namespace时查询所有namespace
+		list, err := c.DynamicClient.Resource(mapping.Resource).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			obj.SetGroupVersionKind(gvk)
+			key, err := c.objectKey(obj)
+			if err != nil {
+				return nil, err
+			}
+			if keep[key] || obj.GetDeletionTimestamp() != nil {
+				continue
+			}
+			if _, ok := obj.GetAnnotations()[ApplySetAnnotation]; !ok {
+				continue
+			}
+			candidates = append(candidates, obj)
+		}
+	}
+	return candidates, nil
+}
+
+// prune 删除不在本次yaml中的对象，返回每个对象的结果和删除失败的信息
+func (c *Tools) prune(ctx context.Context, o *manifestOptions, applied []*unstructured.Unstructured) ([]ObjectResult, []string) {
+	candidates, err := c.pruneCandidates(ctx, o, applied)
+	if err != nil {
+		return nil, []string{fmt.Sprintf("查找需要清理的对象失败: %s", err.Error())}
+	}
+	var results []ObjectResult
+	var errMsgList []string
+	propagationPolicy := metav1.DeletePropagationBackground
+	for _, obj := range candidates {
+		log.Warnf("Namespace: %s, Name: %s, DryRun: %s, Prune %s!", obj.GetNamespace(), obj.GetName(), o.dryRun, obj.GetKind())
+		if o.dryRun == DryRunClient {
+			results = append(results, newObjectResult(0, obj, ActionDeleted))
+			continue
+		}
+		uid := obj.GetUID()
+		dynamicResourceInterface, err := c.resourceInterface(obj)
+		if err == nil {
+			err = dynamicResourceInterface.Delete(ctx, obj.GetName(), metav1.DeleteOptions{
+				PropagationPolicy: &propagationPolicy,
+				DryRun:            o.dryRun.metaDryRun(),
+				// 只删除查询到的那个对象，避免误删同名的新对象
+				Preconditions: &metav1.Preconditions{UID: &uid},
+			})
+		}
+		if err != nil {
+			errMsgList = append(errMsgList, fmt.Sprintf("清理%s %s/%s失败: %s", obj.GetKind(), obj.GetNamespace(), obj.GetName(), err.Error()))
+			results = append(results, failedObjectResult(0, obj, err))
+			continue
+		}
+		results = append(results, newObjectResult(0, obj, ActionDeleted))
+	}
+	return results, errMsgList
+}
@@ -0,0 +1,409 @@
+/*
+ * @Time : 2026/10/17 10:12
+ * @Author : diehao.yuan
+ * @Email : diehao.yuan@outlook.com
+ * @File : resource.go
+ */
+package kubeutils
+
+import (
+	"context"
+	"fmt"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/cache"
+	kerrors "kubeutils/utils/errors"
+	"kubeutils/utils/log"
+)
+
+// ResourceClient 是client-go中各资源typed client的公共部分，例如DeploymentInterface、NodeInterface都实现了该接口
+type ResourceClient[T any, L any] interface {
+	Create(context.Context, *T, metav1.CreateOptions) (*T, error)
+	Update(context.Context, *T, metav1.UpdateOptions) (*T, error)
+	Delete(context.Context, string, metav1.DeleteOptions) error
+	Get(context.Context, string, metav1.GetOptions) (*T, error)
+	List(context.Context, metav1.ListOptions) (*L, error)
+	Watch(context.Context, metav1.ListOptions) (watch.Interface, error)
+	Patch(context.Context, string, types.PatchType, []byte, metav1.PatchOptions, ...string) (*T, error)
+}
+
+// Kind 描述一种资源类型，新增资源类型时只需要注册一个Kind即可，无需再新增文件
+type Kind[T any, L any] struct {
+	// GroupVersionKind 从client-go的scheme中获取，用于填充返回对象的TypeMeta
+	GroupVersionKind schema.GroupVersionKind
+	// Namespaced 为false时代表集群级别的资源，例如Node、Namespace，此时会忽略传入的namespace
+	Namespaced bool
+	// Client 根据clientset和namespace返回对应资源的typed client
+	Client func(kubernetes.Interface, string) ResourceClient[T, L]
+	// DeleteCollection 为false时代表apiserver不支持deletecollection，例如Service、Namespace，
+	// 此时DeleteCollection会先查询再逐个删除。typed client都有DeleteCollection方法，无法通过类型断言判断
+	DeleteCollection bool
+}
+
+// NewNamespacedKind 注册一个命名空间级别的资源类型
+func NewNamespacedKind[T any, L any](client func(kubernetes.Interface, string) ResourceClient[T, L]) *Kind[T, L] {
+	return &Kind[T, L]{
+		GroupVersionKind: kindFromScheme(new(T)),
+		Namespaced:       true,
+		Client:           client,
+		DeleteCollection: true,
+	}
+}
+
+// NewClusterKind 注册一个集群级别的资源类型
+func NewClusterKind[T any, L any](client func(kubernetes.Interface) ResourceClient[T, L]) *Kind[T, L] {
+	return &Kind[T, L]{
+		GroupVersionKind: kindFromScheme(new(T)),
+		Namespaced:       false,
+		Client: func(clientset kubernetes.Interface, _ string) ResourceClient[T, L] {
+			return client(clientset)
+		},
+		DeleteCollection: true,
+	}
+}
+
+// withoutDeleteCollection 标记资源类型不支持deletecollection
+func withoutDeleteCollection[T any, L any](kind *Kind[T, L]) *Kind[T, L] {
+	kind.DeleteCollection = false
+	return kind
+}
+
+// kindFromScheme 从client-go的scheme中查找对象的GVK
+func kindFromScheme(obj any) schema.GroupVersionKind {
+	o, ok := obj.(runtime.Object)
+	if !ok {
+		panic(fmt.Sprintf("%T没有实现runtime.Object", obj))
+	}
+	gvks, _, err := scheme.Scheme.ObjectKinds(o)
+	if err != nil || len(gvks) == 0 {
+		panic(fmt.Sprintf("%T没有在scheme中注册: %v", obj, err))
+	}
+	return gvks[0]
+}
+
+// TypedKubeUtilser 和KubeUtilser一致，只是List和Get返回具体的类型，不需要再做类型断言。
+// 所有资源类型遵循相同的约定：出错时返回nil和经过kubeutils/utils/errors分类的错误，
+// 成功时返回的对象都填充了从scheme中获取的TypeMeta，List没有结果时返回空切片而不是nil
+type TypedKubeUtilser[T any] interface {
+	Create(string) error
+	Delete(string, string, *int64) error
+	DeleteList(string, []string, *int64) error
+	Update(string) error
+	List(string, string, string) ([]T, error)
+	Get(string, string) (*T, error)
+
+	CreateWithContext(context.Context, string) error
+	DeleteWithContext(context.Context, string, string, *int64) error
+	DeleteListWithContext(context.Context, string, []string, *int64) error
+	UpdateWithContext(context.Context, string) error
+	ListWithContext(context.Context, string, string, string) ([]T, error)
+	GetWithContext(context.Context, string, string) (*T, error)
+
+	Upsert(string) error
+	UpsertWithContext(context.Context, string) error
+	Mutate(string, string, func(*T) error) (*T, error)
+	MutateWithContext(context.Context, string, string, func(*T) error) (*T, error)
+	Patch(string, string, types.PatchType, []byte) (*T, error)
+	PatchWithContext(context.Context, string, string, types.PatchType, []byte) (*T, error)
+	Watch(context.Context, string, string, string) (<-chan WatchEvent[T], error)
+}
+
+// Resource 是通用的资源客户端，T为资源类型，L为资源列表类型，例如Resource[appsv1.Deployment, appsv1.DeploymentList]
+type Resource[T any, L any] struct {
+	Clientset kubernetes.Interface
+	// WatchClientset 用于Watch和Inform，不能设置请求超时，为nil时使用Clientset
+	WatchClientset kubernetes.Interface
+	Kind           *Kind[T, L]
+	Item           *T
+	// Concurrency 批量操作时的并发数，为0时使用DefaultConcurrency
+	Concurrency int
+	// Backoff 冲突时重试的间隔和次数，Steps为0时使用retry.DefaultRetry
+	Backoff wait.Backoff
+}
+
+// NewResource 用于生成一个通用的资源客户端，kubeconfig错误时返回错误
+func NewResource[T any, L any](kubeconfig string, kind *Kind[T, L], item *T, opts ...Option) (*Resource[T, L], error) {
+	// 首先生成一个ResourceInstance的实例，并配置默认值和生成clientset
+	instance, err := NewResourceInstance(kubeconfig, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewResourceFromInstance(instance, kind, item), nil
+}
+
+// NewResourceFromInstance 使用已有的ResourceInstance生成资源客户端，多个客户端可以共用同一个clientset
+func NewResourceFromInstance[T any, L any](instance *ResourceInstance, kind *Kind[T, L], item *T) *Resource[T, L] {
+	resource := Resource[T, L]{}
+	resource.Clientset = instance.Clientset
+	resource.WatchClientset = instance.WatchClientset
+	resource.Kind = kind
+	resource.Item = item
+	return &resource
+}
+
+// client 返回对应namespace的typed client，集群级别的资源会忽略namespace
+func (c *Resource[T, L]) client(namespace string) ResourceClient[T, L] {
+	if !c.Kind.Namespaced {
+		namespace = ""
+	}
+	return c.Kind.Client(c.Clientset, namespace)
+}
+
+// watchClient 返回用于watch的typed client，没有请求超时
+func (c *Resource[T, L]) watchClient(namespace string) ResourceClient[T, L] {
+	if c.WatchClientset == nil {
+		return c.client(namespace)
+	}
+	if !c.Kind.Namespaced {
+		namespace = ""
+	}
+	return c.Kind.Client(c.WatchClientset, namespace)
+}
+
+// setTypeMeta 填充TypeMeta，apiserver返回的对象中apiVersion和kind为空
+func (c *Resource[T, L]) setTypeMeta(item *T) {
+	if item == nil {
+		return
+	}
+	if o, ok := any(item).(runtime.Object); ok {
+		o.GetObjectKind().SetGroupVersionKind(c.Kind.GroupVersionKind)
+	}
+}
+
+// itemName 获取Item的名称
+func (c *Resource[T, L]) itemName() string {
+	if c.Item == nil {
+		return ""
+	}
+	if o, ok := any(c.Item).(metav1.Object); ok {
+		return o.GetName()
+	}
+	return ""
+}
+
+// checkItem 创建和更新前检查Item，Item为空时返回ErrInvalidManifest，避免访问nil指针
+func (c *Resource[T, L]) checkItem() error {
+	if c.Item == nil {
+		return kerrors.New(kerrors.ErrInvalidManifest, fmt.Errorf("%s的Item为空", c.Kind.GroupVersionKind.Kind))
+	}
+	return nil
+}
+
+// Untyped 返回实现了KubeUtilser接口的客户端，List和Get返回interface{}
+func (c *Resource[T, L]) Untyped() KubeUtilser {
+	return untypedResource[T, L]{c}
+}
+
+// 创建资源
+func (c *Resource[T, L]) Create(namespace string) error {
+	return c.CreateWithContext(context.TODO(), namespace)
+}
+
+// 创建资源，ctx用于取消请求或设置超时
+func (c *Resource[T, L]) CreateWithContext(ctx context.Context, namespace string) error {
+	_, err := c.CreateWithOptions(ctx, namespace, WriteOptions{})
+	return err
+}
+
+// 删除资源
+func (c *Resource[T, L]) Delete(namespace, name string, gracePeriodSeconds *int64) error {
+	return c.DeleteWithContext(context.TODO(), namespace, name, gracePeriodSeconds)
+}
+
+// 删除资源，ctx用于取消请求或设置超时
+func (c *Resource[T, L]) DeleteWithContext(ctx context.Context, namespace, name string, gracePeriodSeconds *int64) error {
+	// gracePeriodSeconds可配置，如果为0代表是强制删除
+	return c.DeleteWithOptions(ctx, namespace, name, DeleteOptions{GracePeriodSeconds: gracePeriodSeconds})
+}
+
+// 删除多个资源
+func (c *Resource[T, L]) DeleteList(namespace string, nameList []string, gracePeriodSeconds *int64) error {
+	return c.DeleteListWithContext(context.TODO(), namespace, nameList, gracePeriodSeconds)
+}
+
+// 删除多个资源，ctx用于取消请求或设置超时，部分失败时返回聚合后的错误，资源不存在不算失败
+func (c *Resource[T, L]) DeleteListWithContext(ctx context.Context, namespace string, nameList []string, gracePeriodSeconds *int64) error {
+	result := c.DeleteListWithResult(ctx, namespace, nameList, gracePeriodSeconds)
+	return result.Err()
+}
+
+// DeleteListWithResult 并发删除多个资源，返回每个资源的删除结果，并发数由Concurrency控制
+func (c *Resource[T, L]) DeleteListWithResult(ctx context.Context, namespace string, nameList []string, gracePeriodSeconds *int64) *BatchResult {
+	return runBatch(ctx, c.Concurrency, nameList, func(name string) error {
+		return c.DeleteWithContext(ctx, namespace, name, gracePeriodSeconds)
+	})
+}
+
+// DeleteCollectionOptions 按条件批量删除时的配置
+type DeleteCollectionOptions struct {
+	// GracePeriodSeconds 为0代表强制删除
+	GracePeriodSeconds *int64
+	// PropagationPolicy 依赖资源的删除策略，为空时使用资源默认的策略
+	PropagationPolicy metav1.DeletionPropagation
+	// DryRun 为true时只查询并返回会被删除的资源，不执行删除
+	DryRun bool
+	// All 为true时允许labelSelector和fieldSelector都为空，删除全部资源，
+	// namespace为空或者集群级别的资源时是整个集群的资源，例如全部Node或Namespace
+	All bool
+}
+
+// collectionDeleter 支持deletecollection的typed client，是否真正支持由Kind.DeleteCollection决定
+type collectionDeleter interface {
+	DeleteCollection(context.Context, metav1.DeleteOptions, metav1.ListOptions) error
+}
+
+// 按标签和字段选择器批量删除资源
+func (c *Resource[T, L]) DeleteCollection(namespace, labelSelector, fieldSelector string, opts DeleteCollectionOptions) ([]T, error) {
+	return c.DeleteCollectionWithContext(context.TODO(), namespace, labelSelector, fieldSelector, opts)
+}
+
+// 按标签和字段选择器批量删除资源，ctx用于取消请求或设置超时。
+// Kind.DeleteCollection为true时由apiserver一次删除，为false或者apiserver返回405时先查询再逐个删除；
+// 选择器都为空时需要设置All；DryRun时返回会被删除的资源，否则返回nil
+func (c *Resource[T, L]) DeleteCollectionWithContext(ctx context.Context, namespace, labelSelector, fieldSelector string, opts DeleteCollectionOptions) ([]T, error) {
+	if opts.DryRun {
+		return c.ListWithContext(ctx, namespace, labelSelector, fieldSelector)
+	}
+	if labelSelector == "" && fieldSelector == "" && !opts.All {
+		return nil, kerrors.New(kerrors.ErrInvalidManifest, fmt.Errorf("批量删除%s时labelSelector和fieldSelector不能都为空，删除全部时需要设置All", c.Kind.GroupVersionKind.Kind))
+	}
+	log.Warnf("Namespace: %s, LabelSelector: %s, FieldSelector: %s, Delete %s Collection!", namespace, labelSelector, fieldSelector, c.Kind.GroupVersionKind.Kind)
+	listOptions := metav1.ListOptions{
+		FieldSelector: fieldSelector,
+		LabelSelector: labelSelector,
+	}
+	deleteOptions := metav1.DeleteOptions{GracePeriodSeconds: opts.GracePeriodSeconds}
+	if opts.PropagationPolicy != "" {
+		deleteOptions.PropagationPolicy = &opts.PropagationPolicy
+	}
+	if client, ok := c.client(namespace).(collectionDeleter); ok && c.Kind.DeleteCollection {
+		err := client.DeleteCollection(ctx, deleteOptions, listOptions)
+		if !apierrors.IsMethodNotSupported(err) {
+			return nil, kerrors.Classify(err)
+		}
+	}
+
+	// 不支持deletecollection时，先查询再逐个删除。namespace为空时会查询所有namespace，
+	// 因此每个对象都使用自己的namespace删除，结果中的名称为namespace/name
+	items, err := c.ListWithContext(ctx, namespace, labelSelector, fieldSelector)
+	if err != nil {
+		return nil, err
+	}
+	keyList := make([]string, 0, len(items))
+	for i := range items {
+		if o, ok := any(&items[i]).(metav1.Object); ok {
+			keyList = append(keyList, cache.MetaObjectToName(o).String())
+		}
+	}
+	result := runBatch(ctx, c.Concurrency, keyList, func(key string) error {
+		itemNamespace, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			return err
+		}
+		return c.DeleteWithOptions(ctx, itemNamespace, name, DeleteOptions{
+			GracePeriodSeconds: opts.GracePeriodSeconds,
+			PropagationPolicy:  opts.PropagationPolicy,
+		})
+	})
+	return nil, result.Err()
+}
+
+// 更新资源
+func (c *Resource[T, L]) Update(namespace string) error {
+	return c.UpdateWithContext(context.TODO(), namespace)
+}
+
+// 更新资源，ctx用于取消请求或设置超时
+func (c *Resource[T, L]) UpdateWithContext(ctx context.Context, namespace string) error {
+	_, err := c.UpdateWithOptions(ctx, namespace, WriteOptions{})
+	return err
+}
+
+// 获取资源列表
+func (c *Resource[T, L]) List(namespace, labelSelector, fieldSelector string) ([]T, error) {
+	return c.ListWithContext(context.TODO(), namespace, labelSelector, fieldSelector)
+}
+
+// 获取资源列表，ctx用于取消请求或设置超时，每次查询DefaultPageSize个，自动分页直到查询完毕
+func (c *Resource[T, L]) ListWithContext(ctx context.Context, namespace, labelSelector, fieldSelector string) ([]T, error) {
+	// 有可能是根据查询条件进行查询
+	return c.ListWithOptions(ctx, namespace, ListOptions{
+		FieldSelector: fieldSelector,
+		LabelSelector: labelSelector,
+	})
+}
+
+// listItems 从资源列表中取出Items
+func (c *Resource[T, L]) listItems(list *L) ([]T, error) {
+	if list == nil {
+		return []T{}, nil
+	}
+	objs, err := meta.ExtractList(any(list).(runtime.Object))
+	if err != nil {
+		return nil, err
+	}
+	items := make([]T, 0, len(objs))
+	for _, obj := range objs {
+		item, ok := any(obj).(*T)
+		if !ok {
+			return nil, fmt.Errorf("%s列表中包含未知类型%T", c.Kind.GroupVersionKind.Kind, obj)
+		}
+		c.setTypeMeta(item)
+		items = append(items, *item)
+	}
+	return items, nil
+}
+
+// 获取资源详情
+func (c *Resource[T, L]) Get(namespace, name string) (*T, error) {
+	return c.GetWithContext(context.TODO(), namespace, name)
+}
+
+// 获取资源详情，ctx用于取消请求或设置超时
+func (c *Resource[T, L]) GetWithContext(ctx context.Context, namespace, name string) (*T, error) {
+	log.Infof("Namespace: %s, Name: %s, Get %s Info!", namespace, name, c.Kind.GroupVersionKind.Kind)
+	item, err := c.client(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, kerrors.Classify(err)
+	}
+	c.setTypeMeta(item)
+	return item, nil
+}
+
+// untypedResource 将Resource适配为KubeUtilser接口
+type untypedResource[T any, L any] struct {
+	*Resource[T, L]
+}
+
+func (c untypedResource[T, L]) List(namespace, labelSelector, fieldSelector string) (interface{}, error) {
+	return c.ListWithContext(context.TODO(), namespace, labelSelector, fieldSelector)
+}
+
+func (c untypedResource[T, L]) ListWithContext(ctx context.Context, namespace, labelSelector, fieldSelector string) (interface{}, error) {
+	items, err := c.Resource.ListWithContext(ctx, namespace, labelSelector, fieldSelector)
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (c untypedResource[T, L]) Get(namespace, name string) (interface{}, error) {
+	return c.GetWithContext(context.TODO(), namespace, name)
+}
+
+func (c untypedResource[T, L]) GetWithContext(ctx context.Context, namespace, name string) (interface{}, error) {
+	item, err := c.Resource.GetWithContext(ctx, namespace, name)
+	if err != nil {
+		// 避免返回一个值为nil的*T
+		return nil, err
+	}
+	return item, nil
+}
@@ -0,0 +1,141 @@
+/*
+ * @Time : 2026/10/17 23:10
+ * @Author : diehao.yuan
+ * @Email : diehao.yuan@outlook.com
+ * @File : result.go
+ */
+package kubeutils
+
+import (
+	"context"
+	"errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	kerrors "kubeutils/utils/errors"
+)
+
+// ObjectAction 对一个对象执行的操作
+type ObjectAction string
+
+const (
+	ActionCreated    ObjectAction = "created"
+	ActionConfigured ObjectAction = "configured"
+	ActionUnchanged  ObjectAction = "unchanged"
+	ActionDeleted    ObjectAction = "deleted"
+	ActionFailed     ObjectAction = "failed"
+)
+
+// ObjectResult Tools处理yaml中一个对象的结果
+type ObjectResult struct {
+	// Index 对象所在文档的序号，prune删除的对象为0
+	Index            int
+	GroupVersionKind schema.GroupVersionKind
+	Namespace        string
+	Name             string
+	Action           ObjectAction
+	// Object apiserver持久化后的对象，dry-run时为将要持久化的对象，删除和失败时为yaml中的对象，解析失败时为nil
+	Object *unstructured.Unstructured
+	// Err 失败的原因，Action为failed时不为空
+	Err error
+	// Reason apiserver返回的错误原因，例如NotFound、Conflict、Forbidden，不是apiserver的错误时为空
+	Reason metav1.StatusReason
+}
+
+// newObjectResult 根据对象生成结果
+func newObjectResult(index int, obj *unstructured.Unstructured, action ObjectAction) ObjectResult {
+	return ObjectResult{
+		Index:            index,
+		GroupVersionKind: obj.GroupVersionKind(),
+		Namespace:        obj.GetNamespace(),
+		Name:             obj.GetName(),
+		Action:           action,
+		Object:           obj,
+	}
+}
+
+// failedObjectResult 生成失败的结果，obj为nil时表示解析失败
+func failedObjectResult(index int, obj *unstructured.Unstructured, err error) ObjectResult {
+	result := ObjectResult{Index: index, Action: ActionFailed}
+	if obj != nil {
+		result = newObjectResult(index, obj, ActionFailed)
+	}
+	result.Err = kerrors.Classify(err)
+	result.Reason = apierrors.ReasonForError(err)
+	return result
+}
+
+// manifestErrorIndex 返回解析错误对应的文档序号
+func manifestErrorIndex(err error) int {
+	var manifestErr *ManifestError
+	if errors.As(err, &manifestErr) {
+		return manifestErr.Index
+	}
+	return 0
+}
+
+// applyAction 对比apply前后的对象，判断是created、configured还是unchanged
+func applyAction(live, result *unstructured.Unstructured) ObjectAction {
+	if live == nil {
+		return ActionCreated
+	}
+	liveYAML, liveErr := diffYAML(live)
+	resultYAML, resultErr := diffYAML(result)
+	if liveErr == nil && resultErr == nil && liveYAML == resultYAML {
+		return ActionUnchanged
+	}
+	return ActionConfigured
+}
+
+// liveObject 获取集群中的对象，不存在时返回nil
+func liveObject(ctx context.Context, dynamicResourceInterface dynamic.ResourceInterface, name string) (*unstructured.Unstructured, error) {
+	live, err := dynamicResourceInterface.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return live, err
+}
+
+// failedErrors 返回失败的结果中的错误
+func failedErrors(results []ObjectResult) []error {
+	var errList []error
+	for _, result := range results {
+		if result.Action == ActionFailed {
+			errList = append(errList, result.Err)
+		}
+	}
+	return errList
+}
+
+// succeededObjects 返回成功的结果中的对象，用于等待就绪和prune
+func succeededObjects(results []ObjectResult) []*unstructured.Unstructured {
+	var objects []*unstructured.Unstructured
+	for _, result := range results {
+		if result.Action != ActionFailed && result.Object != nil {
+			objects = append(objects, result.Object)
+		}
+	}
+	return objects
+}
+
+// CreateWithResults 创建资源并返回每个对象的结果，msg和err与CreateWithContext一致
+func (c *Tools) CreateWithResults(ctx context.Context, yamlContent string, opts ...ManifestOption) (results []ObjectResult, msg string, err error) {
+	return c.createOrUpdate(ctx, yamlContent, "Create", opts)
+}
+
+// UpdateWithResults 更新资源并返回每个对象的结果
+func (c *Tools) UpdateWithResults(ctx context.Context, yamlContent string, opts ...ManifestOption) (results []ObjectResult, msg string, err error) {
+	return c.createOrUpdate(ctx, yamlContent, "Update", opts)
+}
+
+// ApplyWithResults 应用资源并返回每个对象的结果，开启prune时被清理的对象也会包含在结果中
+func (c *Tools) ApplyWithResults(ctx context.Context, yamlContent string, opts ...ManifestOption) (results []ObjectResult, msg string, err error) {
+	return c.createOrUpdate(ctx, yamlContent, "Apply", opts)
+}
+
+// DeleteWithResults 删除资源并返回每个对象的结果
+func (c *Tools) DeleteWithResults(ctx context.Context, yamlContent string, opts ...ManifestOption) (results []ObjectResult, msg string, err error) {
+	return c.createOrUpdate(ctx, yamlContent, "Delete", opts)
+}
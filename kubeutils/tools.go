@@ -10,14 +10,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	kerrors "kubeutils/utils/errors"
 	"kubeutils/utils/logs"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/runtime/serializer/yaml"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/clientcmd"
 	"strings"
 	"time"
 )
@@ -26,154 +26,314 @@ import (
 type Tools struct {
 	ClusterId     string
 	DynamicClient *dynamic.DynamicClient
+	// RESTMapper 基于discovery将Kind解析为Resource，结果会被缓存
+	RESTMapper meta.ResettableRESTMapper
+	// FieldManager 创建、更新和应用资源时使用的field manager，为空时使用DefaultFieldManager
+	FieldManager string
 }
 
-func NewClientSet(kubeconfig string, timeout int) (clientset *kubernetes.Clientset, err error) {
-	config, err := clientcmd.RESTConfigFromKubeConfig([]byte(kubeconfig))
+func NewClientSet(kubeconfig string, timeout int, opts ...Option) (clientset *kubernetes.Clientset, err error) {
+	// 设置超时时间，opts中的WithTimeout优先
+	opts = append([]Option{WithTimeout(time.Duration(timeout) * time.Second)}, opts...)
+	o := newClientOptions(opts)
+	config, err := restConfigFromKubeconfig(kubeconfig, o.contextName)
 	if err != nil {
-		msg := "解析kubeconfig错误: " + err.Error()
-		return nil, errors.New(msg)
+		return nil, newClientError(ErrInvalidKubeconfig, err)
+	}
+	if err := o.applyTo(config); err != nil {
+		return nil, newClientError(ErrInvalidKubeconfig, err)
 	}
-
-	// 设置超时时间
-	config.Timeout = time.Duration(timeout) * time.Second
 	clientset, err = kubernetes.NewForConfig(config)
 	if err != nil {
-		msg := "创建clientset失败: " + err.Error()
-		return nil, errors.New(msg)
+		return nil, classifyConfigError(config, err)
 	}
 	return clientset, nil
 }
 
 // New函数可以用于配置一些默认值
-func NewTools(kubeconfig string) (tools *Tools, err error) {
-	// 加载kubeconfig文件，并创建restConfig
-	config, _ := clientcmd.RESTConfigFromKubeConfig([]byte(kubeconfig))
-	// 创建一个discovery客户端，这个客户端用于发现k8s集群可以支持的资源类型，同时一些自定义资源也可以使用该客户端进行发现
-	// discoveryClient, _ := discovery.NewDiscoveryClientForConfig(config)
-	// 创建dynamic client，用于创建k8s非结构化的数据，也就是Unstructured类型的数据
-	// k8s自带的核心资源比如deployment、service，都是结构化数据，这些结构化数据都实现了统一的接口，也就是Object.runtime，这些类型可以使用clientset创建
-	// 但是非结构化的数据需要使用dynamic client创建
-	dynamicClient, err := dynamic.NewForConfig(config)
-	// 创建一个非结构化数据对象，用于接受解析的yaml文件内容
-	// obj := &unstructured.Unstructured{}
-	// // GVK：Group Version Kind
-	// _, gvk, err := serializeryaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme).Decode([]byte(yaml), nil, obj)
-	// if err != nil {
-	// 	return nil, err
-	// }
-	// // 获取非结构化数据的namespace
-	// namespace := obj.GetNamespace()
-	// if namespace == "" {
-	// 	namespace = "default"
-	// }
-	// name := obj.GetName()
-	// // 创建一个gvr：Group Version Resource  Resource=Kind + s example deployments
-	// resource := gvk.Kind + "s"
-	// gvr := schema.GroupVersionResource{
-	// 	Group:    gvk.Group,
-	// 	Version:  gvk.Version,
-	// 	Resource: strings.ToLower(resource),
-	// }
-	// // 创建dynamic资源接口
-	// dynamicResourceInterface := dynamicClient.Resource(gvr).Namespace(namespace)
-	// tools.DynamicClient = dynamicResourceInterface
-	// tools.Obj = obj
-	// tools.Name = name
-	tools = &Tools{}
-	tools.DynamicClient = dynamicClient
-	return tools, err
-}
-
-func createOrUpdate(dynamicClient *dynamic.DynamicClient, yamlContent, method string) (string, error) {
-	// 拆分yaml
+func NewTools(kubeconfig string, opts ...Option) (tools *Tools, err error) {
+	// 加载kubeconfig文件，创建restConfig和各个客户端
+	// dynamic client用于创建k8s非结构化的数据，也就是Unstructured类型的数据，
+	// discovery client用于发现k8s集群可以支持的资源类型，同时一些自定义资源也可以使用该客户端进行发现
+	instance, err := NewResourceInstance(kubeconfig, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewToolsFromInstance("", instance), nil
+}
+
+// NewToolsFromInstance 使用已有的ResourceInstance生成Tools，和其他客户端共用同一个dynamic client和RESTMapper
+func NewToolsFromInstance(clusterId string, instance *ResourceInstance) *Tools {
+	tools := &Tools{}
+	tools.ClusterId = clusterId
+	tools.DynamicClient = instance.DynamicClient
+	tools.RESTMapper = instance.RESTMapper
+	return tools
+}
+
+// restMapping 通过RESTMapper将GVK解析为GVR和作用域
+func (c *Tools) restMapping(gvk schema.GroupVersionKind) (*meta.RESTMapping, error) {
+	mapping, err := c.RESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		// 可能是新安装的CRD，清空discovery缓存后重试一次
+		c.RESTMapper.Reset()
+		mapping, err = c.RESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	}
+	return mapping, err
+}
+
+// resourceInterface 通过RESTMapper将GVK解析为GVR，并根据资源的作用域生成dynamic资源接口
+func (c *Tools) resourceInterface(obj *unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	mapping, err := c.restMapping(obj.GroupVersionKind())
+	if err != nil {
+		return nil, err
+	}
+	// 集群级别的资源，例如Namespace、ClusterRole，不需要设置namespace
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return c.DynamicClient.Resource(mapping.Resource), nil
+	}
+	namespace := obj.GetNamespace()
+	if namespace == "" {
+		namespace = "default"
+	}
+	return c.DynamicClient.Resource(mapping.Resource).Namespace(namespace), nil
+}
+
+// 各操作对应的提示信息
+var methodMessages = map[string]string{
+	"Create": "创建",
+	"Update": "更新",
+	"Apply":  "应用",
+	"Delete": "删除",
+}
+
+// createOrUpdate 执行Create、Update、Apply或Delete，返回每个对象的结果，msg为所有失败信息
+func (c *Tools) createOrUpdate(ctx context.Context, yamlContent, method string, opts []ManifestOption) ([]ObjectResult, string, error) {
+	o := c.newManifestOptions(opts)
+	var results []ObjectResult
+	dryRun := o.dryRun.metaDryRun()
+	// 解析yaml或json，解析失败的项直接记录错误，不影响其他项
 	var errMsgList []string
-	methodMsg := "创建"
-	yamlList := strings.Split(yamlContent, "---")
-	// 循环yaml列表
-	for k, v := range yamlList {
-		// 去除空行和\n
-		if v == "" || v == "\n" {
-			continue
+	var conflicts []FieldConflict
+	methodMsg := methodMessages[method]
+	objects, parseErrList := ParseManifests(yamlContent)
+	for _, parseErr := range parseErrList {
+		errMsgList = append(errMsgList, parseErr.Error())
+		results = append(results, failedObjectResult(manifestErrorIndex(parseErr), nil, parseErr))
+	}
+	if method == "Apply" {
+		if err := o.validatePrune(); err != nil {
+			return nil, err.Error(), err
 		}
-		index := k + 1
-		logs.Debug(map[string]interface{}{"yaml": v, "index": index}, "基于yaml创建或更新")
-		// 创建一个非结构化数据对象，用于接受解析的yaml文件内容
-		obj := &unstructured.Unstructured{}
-		// GVK：Group Version Kind
-		_, gvk, err := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme).Decode([]byte(v), nil, obj)
+	}
+	// 按依赖关系排序，例如先创建Namespace和CRD，再创建使用它们的资源，删除时顺序相反
+	sortManifestObjects(objects, method == "Delete")
+	// 已经创建成功的CRD在results中的下标，在创建自定义资源之前需要等待它们可用
+	var crdResults []int
+	// 循环解析出来的对象
+	for _, item := range objects {
+		index := item.Index
+		obj := item.Object
+		if len(crdResults) > 0 && !isCRD(obj) {
+			errMsgList = append(errMsgList, c.waitForCRDResults(ctx, results, crdResults)...)
+			crdResults = nil
+		}
+		logs.Debug(map[string]interface{}{"kind": obj.GetKind(), "name": obj.GetName(), "index": index, "line": item.Line}, "基于yaml创建或更新")
+		if method == "Apply" {
+			o.markApplySet(obj)
+		}
+		// 创建dynamic资源接口
+		dynamicResourceInterface, err := c.resourceInterface(obj)
 		if err != nil {
-			// 如果此处失败，说明这一列的yaml内容有问题，直接下一个即可
-			msg := fmt.Sprintf("第%d项yaml数据序列化: %s \n", index, err.Error())
+			msg := fmt.Sprintf("第%d项yaml数据无法解析资源类型: %s", index, err.Error())
 			errMsgList = append(errMsgList, msg)
+			results = append(results, failedObjectResult(index, obj, err))
 			continue
 		}
-		// yaml序列化成功继续往下执行
-		// 获取非结构化数据的namespace
-		namespace := obj.GetNamespace()
-		if namespace == "" {
-			namespace = "default"
-		}
-		// 创建一个gvr：Group Version Resource  Resource=Kind + s example deployments
-		resource := gvk.Kind + "s"
-		gvr := schema.GroupVersionResource{
-			Group:    gvk.Group,
-			Version:  gvk.Version,
-			Resource: strings.ToLower(resource),
+		// 客户端dry-run只解析资源类型，不发送写请求
+		if o.dryRun == DryRunClient {
+			results = append(results, newObjectResult(index, obj.DeepCopy(), clientDryRunActions[method]))
+			continue
 		}
-		// 创建dynamic资源接口
-		dynamicResourceInterface := dynamicClient.Resource(gvr).Namespace(namespace)
+		var result *unstructured.Unstructured
+		var action ObjectAction
 		switch method {
 		case "Create":
-			_, err = dynamicResourceInterface.Create(context.TODO(), obj, metav1.CreateOptions{})
+			action = ActionCreated
+			result, err = dynamicResourceInterface.Create(ctx, obj, metav1.CreateOptions{FieldManager: o.fieldManager, DryRun: dryRun})
 		case "Update":
-			methodMsg = "更新"
-			_, err = dynamicResourceInterface.Update(context.TODO(), obj, metav1.UpdateOptions{})
+			action = ActionConfigured
+			// server dry-run返回的resourceVersion不会变化，需要获取集群中的对象对比内容
+			var live *unstructured.Unstructured
+			if o.dryRun == DryRunServer {
+				live, err = liveObject(ctx, dynamicResourceInterface, obj.GetName())
+				if err != nil {
+					break
+				}
+			}
+			result, err = dynamicResourceInterface.Update(ctx, obj, metav1.UpdateOptions{FieldManager: o.fieldManager, DryRun: dryRun})
+			if err != nil {
+				break
+			}
+			if live != nil {
+				action = applyAction(live, result)
+			} else if obj.GetResourceVersion() != "" && result.GetResourceVersion() == obj.GetResourceVersion() {
+				// 内容没有变化时apiserver不会更新resourceVersion
+				action = ActionUnchanged
+			}
 		case "Apply":
-			methodMsg = "应用"
+			// 先获取集群中的对象，用于判断apply之后是否有变化
 			name := obj.GetName()
-			_, err = dynamicResourceInterface.Apply(context.TODO(), name, obj, metav1.ApplyOptions{})
+			var live *unstructured.Unstructured
+			live, err = liveObject(ctx, dynamicResourceInterface, name)
+			if err != nil {
+				break
+			}
+			// server-side apply必须指定FieldManager，Force为true时接管其他manager的字段
+			result, err = dynamicResourceInterface.Apply(ctx, name, obj, metav1.ApplyOptions{FieldManager: o.fieldManager, Force: o.force, DryRun: dryRun})
+			if err == nil {
+				action = applyAction(live, result)
+			}
 		case "Delete":
-			methodMsg = "删除"
+			// 删除请求不返回对象，结果中使用yaml中的对象
 			name := obj.GetName()
-			err = dynamicResourceInterface.Delete(context.TODO(), name, metav1.DeleteOptions{})
+			action = ActionDeleted
+			result = obj
+			err = dynamicResourceInterface.Delete(ctx, name, metav1.DeleteOptions{DryRun: dryRun})
 		}
 		if err != nil {
 			msg := fmt.Sprintf("第%d项yaml数据%s失败: %s", index, methodMsg, err.Error())
 			errMsgList = append(errMsgList, msg)
+			conflicts = append(conflicts, fieldConflicts(index, obj, err)...)
+			results = append(results, failedObjectResult(index, obj, err))
 			continue
 		}
+		// dry-run时CRD并没有真正创建，不需要等待
+		if method != "Delete" && o.dryRun == DryRunNone && isCRD(obj) {
+			crdResults = append(crdResults, len(results))
+		}
+		results = append(results, newObjectResult(index, result, action))
+	}
+	// 全部应用成功时才清理旧对象，避免因为部分失败误删仍在使用的对象
+	if method == "Apply" && o.pruneSetID != "" && len(errMsgList) == 0 {
+		pruned, pruneErrMsgList := c.prune(ctx, o, succeededObjects(results))
+		results = append(results, pruned...)
+		errMsgList = append(errMsgList, pruneErrMsgList...)
+	}
+	// 等待成功的对象就绪，只有等待失败是唯一的错误时直接返回WaitError
+	var waitErr error
+	if o.waitTimeout > 0 && method != "Delete" && o.dryRun == DryRunNone {
+		var waitObjects []*unstructured.Unstructured
+		for _, result := range results {
+			if result.Action != ActionFailed && result.Action != ActionDeleted {
+				waitObjects = append(waitObjects, result.Object)
+			}
+		}
+		if len(waitObjects) > 0 {
+			if waitErr = c.WaitWithContext(ctx, waitObjects, o.waitTimeout); waitErr != nil {
+				errMsgList = append(errMsgList, waitErr.Error())
+			}
+		}
 	}
 	if len(errMsgList) == 0 {
-		return "", nil
+		return results, "", nil
+	} else if len(conflicts) > 0 {
+		// 同时返回其他对象的错误，调用方可以通过errors.As获取ConflictError，也可以通过errors.Is判断其他错误
+		conflictErr := &ConflictError{Conflicts: conflicts}
+		return results, strings.Join(errMsgList, "\n"), errors.Join(append([]error{conflictErr}, failedErrors(results)...)...)
+	} else if waitErr != nil && len(errMsgList) == 1 {
+		return results, waitErr.Error(), waitErr
 	} else {
 		errMsg := fmt.Sprintf("%s失败", methodMsg)
-		return strings.Join(errMsgList, "\n"), errors.New(errMsg)
+		return results, strings.Join(errMsgList, "\n"), kerrors.Join(errMsg, failedErrors(results)...)
 	}
 
 }
 
+// waitForCRDResults 等待results中下标为crdResults的CRD可用，没有变为Established的CRD的结果改为失败，返回失败信息
+func (c *Tools) waitForCRDResults(ctx context.Context, results []ObjectResult, crdResults []int) []string {
+	names := make([]string, 0, len(crdResults))
+	for _, i := range crdResults {
+		names = append(names, results[i].Name)
+	}
+	failed := c.waitForCRDsEstablished(ctx, names)
+	var errMsgList []string
+	for _, i := range crdResults {
+		err, ok := failed[results[i].Name]
+		if !ok {
+			continue
+		}
+		errMsgList = append(errMsgList, fmt.Sprintf("第%d项yaml数据等待CRD可用失败: %s", results[i].Index, err.Error()))
+		results[i] = failedObjectResult(results[i].Index, results[i].Object, err)
+	}
+	return errMsgList
+}
+
+// 客户端dry-run时各操作对应的结果
+var clientDryRunActions = map[string]ObjectAction{
+	"Create": ActionCreated,
+	"Update": ActionConfigured,
+	"Apply":  ActionConfigured,
+	"Delete": ActionDeleted,
+}
+
 // 创建资源
 func (c *Tools) Create(yamlContent string) (msg string, err error) {
-	msg, err = createOrUpdate(c.DynamicClient, yamlContent, "Create")
+	return c.CreateWithContext(context.TODO(), yamlContent)
+}
+
+// 创建资源，ctx用于取消请求或设置超时
+func (c *Tools) CreateWithContext(ctx context.Context, yamlContent string, opts ...ManifestOption) (msg string, err error) {
+	_, msg, err = c.createOrUpdate(ctx, yamlContent, "Create", opts)
 	return
 }
 
+// 更新资源
 func (c *Tools) Update(yamlContent string) (msg string, err error) {
-	msg, err = createOrUpdate(c.DynamicClient, yamlContent, "Update")
+	return c.UpdateWithContext(context.TODO(), yamlContent)
+}
+
+// 更新资源，ctx用于取消请求或设置超时
+func (c *Tools) UpdateWithContext(ctx context.Context, yamlContent string, opts ...ManifestOption) (msg string, err error) {
+	_, msg, err = c.createOrUpdate(ctx, yamlContent, "Update", opts)
 	return
 }
 
+// 应用资源
 func (c *Tools) Apply(yamlContent string) (msg string, err error) {
-	msg, err = createOrUpdate(c.DynamicClient, yamlContent, "Apply")
+	return c.ApplyWithContext(context.TODO(), yamlContent)
+}
+
+// 应用资源，ctx用于取消请求或设置超时，字段与其他field manager冲突时返回的错误中包含ConflictError，可以通过errors.As获取
+func (c *Tools) ApplyWithContext(ctx context.Context, yamlContent string, opts ...ManifestOption) (msg string, err error) {
+	_, msg, err = c.createOrUpdate(ctx, yamlContent, "Apply", opts)
 	return
 }
 
+// 删除资源
 func (c *Tools) Delete(yamlContent string) (msg string, err error) {
-	msg, err = createOrUpdate(c.DynamicClient, yamlContent, "Delete")
+	return c.DeleteWithContext(context.TODO(), yamlContent)
+}
+
+// 删除资源，ctx用于取消请求或设置超时
+func (c *Tools) DeleteWithContext(ctx context.Context, yamlContent string, opts ...ManifestOption) (msg string, err error) {
+	_, msg, err = c.createOrUpdate(ctx, yamlContent, "Delete", opts)
 	return
 }
 
+// DryRun 以dry-run模式执行Create、Update、Apply或Delete，结果中的Object为apiserver将会持久化的对象，
+// mode为DryRunClient时为本地解析出来的对象，msg和err与CreateWithContext等方法一致
+func (c *Tools) DryRun(ctx context.Context, method, yamlContent string, mode DryRunMode, opts ...ManifestOption) (results []ObjectResult, msg string, err error) {
+	if _, ok := methodMessages[method]; !ok {
+		return nil, "", fmt.Errorf("不支持的操作: %s", method)
+	}
+	if mode == DryRunNone {
+		mode = DryRunServer
+	}
+	opts = append(opts, WithDryRun(mode))
+	return c.createOrUpdate(ctx, yamlContent, method, opts)
+}
+
 // 创建资源
 // func (c *Tools) Create() error {
 // 	_, err := c.DynamicClient.Create(context.TODO(), c.Obj, metav1.CreateOptions{})
@@ -0,0 +1,226 @@
+/*
+ * @Time : 2026/10/18 09:20
+ * @Author : diehao.yuan
+ * @Email : diehao.yuan@outlook.com
+ * @File : upsert.go
+ */
+package kubeutils
+
+import (
+	"context"
+	"fmt"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	kerrors "kubeutils/utils/errors"
+	"kubeutils/utils/log"
+)
+
+// UpsertOptions Upsert时的配置
+type UpsertOptions struct {
+	WriteOptions
+	// PreserveServerFields 为true时，Item中没有设置的由apiserver分配的字段使用集群中的值，
+	// 例如Service的clusterIP和nodePort、工作负载的selector、PVC的volumeName，避免更新时被清空或者因为不可修改而失败
+	PreserveServerFields bool
+}
+
+// Upsert 资源不存在时创建，存在时更新，冲突时自动重试
+func (c *Resource[T, L]) Upsert(namespace string) error {
+	return c.UpsertWithContext(context.TODO(), namespace)
+}
+
+// UpsertWithContext 资源不存在时创建，存在时更新，保留apiserver分配的字段，ctx用于取消请求或设置超时
+func (c *Resource[T, L]) UpsertWithContext(ctx context.Context, namespace string) error {
+	_, _, err := c.UpsertWithOptions(ctx, namespace, UpsertOptions{PreserveServerFields: true})
+	return err
+}
+
+// UpsertWithOptions 资源不存在时创建，存在时获取集群中的对象，使用它的resourceVersion更新，
+// 返回持久化后的对象和执行的操作(ActionCreated、ActionConfigured或ActionUnchanged)。
+// Item没有设置finalizers和ownerReferences时使用集群中的值，避免清除控制器添加的finalizer和owner。
+// 冲突或者创建时已存在会重新获取后重试，重试间隔由Resource.Backoff控制。
+// 客户端dry-run时只查询资源是否存在，存在时返回ActionConfigured，不存在时返回ActionCreated；
+// server dry-run时对比集群中的对象和dry-run的结果判断ActionConfigured还是ActionUnchanged
+func (c *Resource[T, L]) UpsertWithOptions(ctx context.Context, namespace string, opts UpsertOptions) (*T, ObjectAction, error) {
+	log.Infof("Namespace: %s, Name: %s, DryRun: %s, Upsert %s!", namespace, c.itemName(), opts.DryRun, c.Kind.GroupVersionKind.Kind)
+	if err := c.checkItem(); err != nil {
+		return nil, ActionFailed, err
+	}
+	// 只有generateName的对象无法判断是否已经存在
+	if c.itemName() == "" {
+		return nil, ActionFailed, kerrors.New(kerrors.ErrInvalidManifest, fmt.Errorf("%s的name为空，无法Upsert", c.Kind.GroupVersionKind.Kind))
+	}
+	client := c.client(namespace)
+	if opts.DryRun == DryRunClient {
+		_, err := client.Get(ctx, c.itemName(), metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return c.clientDryRunItem(namespace), ActionCreated, nil
+		}
+		if err != nil {
+			return nil, ActionFailed, kerrors.Classify(err)
+		}
+		return c.clientDryRunItem(namespace), ActionConfigured, nil
+	}
+	var result *T
+	var action ObjectAction
+	retryable := func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}
+	err := retry.OnError(c.backoff(), retryable, func() error {
+		live, err := client.Get(ctx, c.itemName(), metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			action = ActionCreated
+			result, err = client.Create(ctx, c.Item, metav1.CreateOptions{DryRun: opts.DryRun.metaDryRun(), FieldManager: opts.FieldManager})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		desired := c.deepCopy(c.Item)
+		liveMeta, desiredMeta := any(live).(metav1.Object), any(desired).(metav1.Object)
+		desiredMeta.SetResourceVersion(liveMeta.GetResourceVersion())
+		preserveMetadata(desiredMeta, liveMeta)
+		if opts.PreserveServerFields {
+			preserveServerFields(any(desired), any(live))
+		}
+		result, err = client.Update(ctx, desired, metav1.UpdateOptions{DryRun: opts.DryRun.metaDryRun(), FieldManager: opts.FieldManager})
+		if err != nil {
+			return err
+		}
+		// 内容没有变化时apiserver不会更新resourceVersion，
+		// server dry-run时返回的resourceVersion始终和live相同，只能对比内容
+		if opts.DryRun == DryRunServer {
+			action = typedUpdateAction(live, result)
+			return nil
+		}
+		action = ActionConfigured
+		if any(result).(metav1.Object).GetResourceVersion() == liveMeta.GetResourceVersion() {
+			action = ActionUnchanged
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, ActionFailed, kerrors.Classify(err)
+	}
+	c.setTypeMeta(result)
+	return result, action, nil
+}
+
+// typedUpdateAction 转换为unstructured后和applyAction一样对比内容，转换失败时视为configured
+func typedUpdateAction(live, result any) ObjectAction {
+	liveObject, err := runtime.DefaultUnstructuredConverter.ToUnstructured(live)
+	if err != nil {
+		return ActionConfigured
+	}
+	resultObject, err := runtime.DefaultUnstructuredConverter.ToUnstructured(result)
+	if err != nil {
+		return ActionConfigured
+	}
+	return applyAction(&unstructured.Unstructured{Object: liveObject}, &unstructured.Unstructured{Object: resultObject})
+}
+
+// backoff 返回冲突重试的间隔，Backoff未设置时使用retry.DefaultRetry
+func (c *Resource[T, L]) backoff() wait.Backoff {
+	if c.Backoff.Steps == 0 {
+		return retry.DefaultRetry
+	}
+	return c.Backoff
+}
+
+// deepCopy 深拷贝资源对象
+func (c *Resource[T, L]) deepCopy(item *T) *T {
+	return any(item).(runtime.Object).DeepCopyObject().(any).(*T)
+}
+
+// preserveMetadata desired没有设置finalizers和ownerReferences时使用live中的值，
+// 例如Service的service.kubernetes.io/load-balancer-cleanup由控制器添加，更新时不能清除
+func preserveMetadata(desired, live metav1.Object) {
+	if desired.GetFinalizers() == nil {
+		desired.SetFinalizers(live.GetFinalizers())
+	}
+	if desired.GetOwnerReferences() == nil {
+		desired.SetOwnerReferences(live.GetOwnerReferences())
+	}
+}
+
+// preserveServerFields 将desired中没有设置的由apiserver分配或者不可修改的字段设置为live中的值
+func preserveServerFields(desired, live any) {
+	switch d := desired.(type) {
+	case *corev1.Service:
+		l := live.(*corev1.Service)
+		if d.Spec.ClusterIP == "" && len(d.Spec.ClusterIPs) == 0 {
+			d.Spec.ClusterIP = l.Spec.ClusterIP
+			d.Spec.ClusterIPs = l.Spec.ClusterIPs
+		}
+		if d.Spec.HealthCheckNodePort == 0 {
+			d.Spec.HealthCheckNodePort = l.Spec.HealthCheckNodePort
+		}
+		// 相同端口和协议的nodePort保持不变，否则更新时会重新分配
+		for i := range d.Spec.Ports {
+			if d.Spec.Ports[i].NodePort != 0 {
+				continue
+			}
+			for _, port := range l.Spec.Ports {
+				if port.Port == d.Spec.Ports[i].Port && port.Protocol == d.Spec.Ports[i].Protocol {
+					d.Spec.Ports[i].NodePort = port.NodePort
+				}
+			}
+		}
+	case *appsv1.Deployment:
+		if d.Spec.Selector == nil {
+			d.Spec.Selector = live.(*appsv1.Deployment).Spec.Selector
+		}
+	case *appsv1.StatefulSet:
+		if d.Spec.Selector == nil {
+			d.Spec.Selector = live.(*appsv1.StatefulSet).Spec.Selector
+		}
+	case *appsv1.DaemonSet:
+		if d.Spec.Selector == nil {
+			d.Spec.Selector = live.(*appsv1.DaemonSet).Spec.Selector
+		}
+	case *appsv1.ReplicaSet:
+		if d.Spec.Selector == nil {
+			d.Spec.Selector = live.(*appsv1.ReplicaSet).Spec.Selector
+		}
+	case *batchv1.Job:
+		l := live.(*batchv1.Job)
+		if d.Spec.Selector == nil {
+			d.Spec.Selector = l.Spec.Selector
+			d.Spec.ManualSelector = l.Spec.ManualSelector
+		}
+		// Job的pod模板不可修改，apiserver会在模板中添加controller-uid等label
+		for key, value := range l.Spec.Template.Labels {
+			if _, ok := d.Spec.Template.Labels[key]; !ok {
+				if d.Spec.Template.Labels == nil {
+					d.Spec.Template.Labels = map[string]string{}
+				}
+				d.Spec.Template.Labels[key] = value
+			}
+		}
+	case *corev1.PersistentVolumeClaim:
+		l := live.(*corev1.PersistentVolumeClaim)
+		if d.Spec.VolumeName == "" {
+			d.Spec.VolumeName = l.Spec.VolumeName
+		}
+		if d.Spec.StorageClassName == nil {
+			d.Spec.StorageClassName = l.Spec.StorageClassName
+		}
+		if d.Spec.VolumeMode == nil {
+			d.Spec.VolumeMode = l.Spec.VolumeMode
+		}
+	case *corev1.PersistentVolume:
+		if d.Spec.ClaimRef == nil {
+			d.Spec.ClaimRef = live.(*corev1.PersistentVolume).Spec.ClaimRef
+		}
+	case *corev1.Pod:
+		if d.Spec.NodeName == "" {
+			d.Spec.NodeName = live.(*corev1.Pod).Spec.NodeName
+		}
+	}
+}
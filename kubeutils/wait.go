@@ -0,0 +1,292 @@
+/*
+ * @Time : 2026/10/17 22:40
+ * @Author : diehao.yuan
+ * @Email : diehao.yuan@outlook.com
+ * @File : wait.go
+ */
+package kubeutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kerrors "kubeutils/utils/errors"
+	"kubeutils/utils/log"
+	"strings"
+	"time"
+)
+
+// 检查资源是否就绪的间隔
+const readinessPollInterval = 2 * time.Second
+
+// WithWaitReady Create、Update、Apply成功后等待对象就绪，超过timeout仍未就绪或者有对象失败时返回WaitError，dry-run时不等待
+func WithWaitReady(timeout time.Duration) ManifestOption {
+	return func(o *manifestOptions) {
+		o.waitTimeout = timeout
+	}
+}
+
+// PendingObject 等待结束时仍未就绪的对象
+type PendingObject struct {
+	Kind      string
+	Namespace string
+	Name      string
+	// Reason 最后一次检查时未就绪的原因
+	Reason string
+	// Failed 为true时代表对象已经失败，不会再就绪，例如Job执行失败
+	Failed bool
+}
+
+// WaitError 等待超时、被取消或者有对象失败时返回，Pending为仍未就绪的对象。
+// 只有等待超时时满足errors.Is(err, ErrTimeout)
+type WaitError struct {
+	Pending []PendingObject
+	// Err 等待超时时为context.DeadlineExceeded，ctx被取消时为context.Canceled，有对象失败时为nil
+	Err error
+}
+
+func (e *WaitError) Error() string {
+	items := make([]string, 0, len(e.Pending))
+	for _, pending := range e.Pending {
+		name := pending.Name
+		if pending.Namespace != "" {
+			name = pending.Namespace + "/" + name
+		}
+		items = append(items, fmt.Sprintf("%s %s: %s", pending.Kind, name, pending.Reason))
+	}
+	prefix := "等待就绪失败"
+	if errors.Is(e.Err, context.DeadlineExceeded) {
+		prefix = "等待就绪超时"
+	} else if e.Err != nil {
+		prefix = "等待就绪被取消"
+	}
+	return fmt.Sprintf("%s，%d个对象未就绪: %s", prefix, len(e.Pending), strings.Join(items, "; "))
+}
+
+func (e *WaitError) Is(target error) bool {
+	return target == kerrors.ErrTimeout && errors.Is(e.Err, context.DeadlineExceeded)
+}
+
+func (e *WaitError) Unwrap() error {
+	return e.Err
+}
+
+// Wait 等待对象就绪
+func (c *Tools) Wait(objects []*unstructured.Unstructured, timeout time.Duration) error {
+	return c.WaitWithContext(context.TODO(), objects, timeout)
+}
+
+// WaitWithContext 等待对象就绪，不同类型的就绪条件如下：
+// Deployment、StatefulSet、DaemonSet滚动更新完成，Job执行成功，PersistentVolumeClaim为Bound，
+// LoadBalancer类型的Service分配了ingress，CRD为Established，Pod为Ready，
+// 其他资源(例如自定义资源)有Ready condition时要求为True，没有时只要求observedGeneration为最新。
+// Job执行失败、Pod的phase为Failed或者Deployment超过progressDeadlineSeconds时不再继续等待，立即返回WaitError
+func (c *Tools) WaitWithContext(ctx context.Context, objects []*unstructured.Unstructured, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	pending := make([]PendingObject, len(objects))
+	for i, obj := range objects {
+		pending[i] = PendingObject{Kind: obj.GetKind(), Namespace: obj.GetNamespace(), Name: obj.GetName(), Reason: "尚未检查"}
+	}
+	remaining := objects
+	failed := false
+	err := wait.PollUntilContextCancel(ctx, readinessPollInterval, true, func(ctx context.Context) (bool, error) {
+		var notReady []*unstructured.Unstructured
+		var notReadyPending []PendingObject
+		for i, obj := range remaining {
+			ready, objectFailed, reason := c.checkReady(ctx, obj)
+			if ready {
+				log.Infof("Namespace: %s, Name: %s, %s Ready!", obj.GetNamespace(), obj.GetName(), obj.GetKind())
+				continue
+			}
+			pending[i].Reason = reason
+			pending[i].Failed = objectFailed
+			failed = failed || objectFailed
+			notReady = append(notReady, obj)
+			notReadyPending = append(notReadyPending, pending[i])
+		}
+		remaining, pending = notReady, notReadyPending
+		// 有对象失败时不会再全部就绪，不需要等到超时
+		return len(remaining) == 0 || failed, nil
+	})
+	if failed {
+		return &WaitError{Pending: pending}
+	}
+	if err != nil {
+		return &WaitError{Pending: pending, Err: ctx.Err()}
+	}
+	return nil
+}
+
+// checkReady 获取集群中的最新对象并判断是否就绪，未就绪时返回原因，failed为true时代表对象已经失败
+func (c *Tools) checkReady(ctx context.Context, obj *unstructured.Unstructured) (ready, failed bool, reason string) {
+	dynamicResourceInterface, err := c.resourceInterface(obj)
+	if err != nil {
+		return false, false, err.Error()
+	}
+	live, err := dynamicResourceInterface.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return false, false, err.Error()
+	}
+	if failed, reason := isFailed(live); failed {
+		return false, true, reason
+	}
+	ready, reason = isReady(live)
+	return ready, false, reason
+}
+
+// isFailed 判断对象是否已经失败，失败的对象不会再就绪
+func isFailed(obj *unstructured.Unstructured) (bool, string) {
+	group := obj.GroupVersionKind().Group
+	switch {
+	case group == "apps" && obj.GetKind() == "Deployment":
+		// 和kubectl rollout status一样，超过progressDeadlineSeconds时直接失败，
+		// controller还没有处理最新的generation时condition可能是上一次滚动更新留下的，不能作为依据
+		observed, _, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+		if observed >= obj.GetGeneration() && hasConditionReason(obj, "Progressing", "ProgressDeadlineExceeded") {
+			return true, "滚动更新超过progressDeadlineSeconds"
+		}
+	case group == "batch" && obj.GetKind() == "Job":
+		if condition := findCondition(obj, "Failed"); condition != nil && condition["status"] == "True" {
+			return true, fmt.Sprintf("Job执行失败: %v", condition["reason"])
+		}
+	case group == "" && obj.GetKind() == "Pod":
+		if phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase"); phase == "Failed" {
+			return true, "Pod的phase为Failed"
+		}
+	}
+	return false, ""
+}
+
+// isReady 根据资源类型判断对象是否就绪
+func isReady(obj *unstructured.Unstructured) (bool, string) {
+	if observed, found, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration"); found && observed < obj.GetGeneration() {
+		return false, fmt.Sprintf("observedGeneration %d落后于generation %d", observed, obj.GetGeneration())
+	}
+	group := obj.GroupVersionKind().Group
+	switch {
+	case group == "apps" && obj.GetKind() == "Deployment":
+		return deploymentReady(obj)
+	case group == "apps" && obj.GetKind() == "StatefulSet":
+		return statefulSetReady(obj)
+	case group == "apps" && obj.GetKind() == "DaemonSet":
+		return daemonSetReady(obj)
+	case group == "batch" && obj.GetKind() == "Job":
+		return hasCondition(obj, "Complete", "True"), "Job尚未执行完成"
+	case group == "" && obj.GetKind() == "PersistentVolumeClaim":
+		phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+		return phase == "Bound", fmt.Sprintf("phase为%s", phase)
+	case group == "" && obj.GetKind() == "Service":
+		serviceType, _, _ := unstructured.NestedString(obj.Object, "spec", "type")
+		if serviceType != "LoadBalancer" {
+			return true, ""
+		}
+		ingress, _, _ := unstructured.NestedSlice(obj.Object, "status", "loadBalancer", "ingress")
+		return len(ingress) > 0, "LoadBalancer尚未分配ingress"
+	case group == "" && obj.GetKind() == "Pod":
+		phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+		if phase == "Succeeded" {
+			return true, ""
+		}
+		return hasCondition(obj, "Ready", "True"), fmt.Sprintf("Pod未Ready，phase为%s", phase)
+	case isCRD(obj):
+		return hasCondition(obj, "Established", "True"), "CRD尚未Established"
+	}
+	if conditionExists(obj, "Ready") {
+		return hasCondition(obj, "Ready", "True"), "Ready condition不为True"
+	}
+	return true, ""
+}
+
+// deploymentReady 和kubectl rollout status的判断一致
+func deploymentReady(obj *unstructured.Unstructured) (bool, string) {
+	replicas := specReplicas(obj)
+	updated, _, _ := unstructured.NestedInt64(obj.Object, "status", "updatedReplicas")
+	current, _, _ := unstructured.NestedInt64(obj.Object, "status", "replicas")
+	available, _, _ := unstructured.NestedInt64(obj.Object, "status", "availableReplicas")
+	switch {
+	case updated < replicas:
+		return false, fmt.Sprintf("已更新%d/%d个副本", updated, replicas)
+	case current > updated:
+		return false, fmt.Sprintf("%d个旧副本等待终止", current-updated)
+	case available < updated:
+		return false, fmt.Sprintf("可用%d/%d个副本", available, updated)
+	}
+	return true, ""
+}
+
+// statefulSetReady 和kubectl rollout status的判断一致，OnDelete策略只要求observedGeneration为最新
+func statefulSetReady(obj *unstructured.Unstructured) (bool, string) {
+	strategy, _, _ := unstructured.NestedString(obj.Object, "spec", "updateStrategy", "type")
+	if strategy == "OnDelete" {
+		return true, ""
+	}
+	replicas := specReplicas(obj)
+	ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+	if ready < replicas {
+		return false, fmt.Sprintf("就绪%d/%d个副本", ready, replicas)
+	}
+	partition, found, _ := unstructured.NestedInt64(obj.Object, "spec", "updateStrategy", "rollingUpdate", "partition")
+	if found && partition > 0 {
+		updated, _, _ := unstructured.NestedInt64(obj.Object, "status", "updatedReplicas")
+		if updated < replicas-partition {
+			return false, fmt.Sprintf("已更新%d/%d个副本", updated, replicas-partition)
+		}
+		return true, ""
+	}
+	currentRevision, _, _ := unstructured.NestedString(obj.Object, "status", "currentRevision")
+	updateRevision, _, _ := unstructured.NestedString(obj.Object, "status", "updateRevision")
+	if currentRevision != updateRevision {
+		return false, fmt.Sprintf("revision %s尚未更新为%s", currentRevision, updateRevision)
+	}
+	return true, ""
+}
+
+// daemonSetReady 和kubectl rollout status的判断一致
+func daemonSetReady(obj *unstructured.Unstructured) (bool, string) {
+	desired, _, _ := unstructured.NestedInt64(obj.Object, "status", "desiredNumberScheduled")
+	updated, _, _ := unstructured.NestedInt64(obj.Object, "status", "updatedNumberScheduled")
+	available, _, _ := unstructured.NestedInt64(obj.Object, "status", "numberAvailable")
+	if updated < desired {
+		return false, fmt.Sprintf("已更新%d/%d个节点", updated, desired)
+	}
+	if available < desired {
+		return false, fmt.Sprintf("可用%d/%d个节点", available, desired)
+	}
+	return true, ""
+}
+
+// specReplicas 返回spec.replicas，未设置时为1
+func specReplicas(obj *unstructured.Unstructured) int64 {
+	replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if !found {
+		return 1
+	}
+	return replicas
+}
+
+// conditionExists 判断status.conditions中是否有指定类型的condition
+func conditionExists(obj *unstructured.Unstructured, conditionType string) bool {
+	return findCondition(obj, conditionType) != nil
+}
+
+// hasConditionReason 判断指定类型的condition的reason
+func hasConditionReason(obj *unstructured.Unstructured, conditionType, reason string) bool {
+	condition := findCondition(obj, conditionType)
+	return condition != nil && condition["reason"] == reason
+}
+
+// findCondition 返回status.conditions中指定类型的condition
+func findCondition(obj *unstructured.Unstructured, conditionType string) map[string]interface{} {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, condition := range conditions {
+		c, ok := condition.(map[string]interface{})
+		if ok && c["type"] == conditionType {
+			return c
+		}
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+/*
+ * @Time : 2026/10/18 11:30
+ * @Author : diehao.yuan
+ * @Email : diehao.yuan@outlook.com
+ * @File : watch.go
+ */
+package kubeutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	kerrors "kubeutils/utils/errors"
+	"kubeutils/utils/log"
+	"time"
+)
+
+// watch断开或者出错后重新连接的间隔
+const watchRetryInterval = time.Second
+
+// WatchEvent Watch返回的事件
+type WatchEvent[T any] struct {
+	// Type 为watch.Added、watch.Modified、watch.Deleted或watch.Error
+	Type watch.EventType
+	// Object 变化后的对象，Deleted时为删除前的对象，Error时为nil
+	Object *T
+	// Err Type为watch.Error时的错误，出错后会自动重试，不会关闭channel
+	Err error
+}
+
+// Watch 监听资源的变化，返回的channel在ctx取消后关闭。
+// 开始时会先查询一次，已有的资源以Added事件返回；连接断开后从最后一个resourceVersion继续监听，
+// resourceVersion过期(410 Gone)时重新查询，并根据和之前的差异补发Added、Modified、Deleted事件，
+// 这种情况下补发的Deleted事件中的对象只有namespace和name；重新查询失败时保留之前的状态并重试，直到查询成功。
+// watch使用没有请求超时的WatchClientset，不会因为DefaultTimeout被断开
+func (c *Resource[T, L]) Watch(ctx context.Context, namespace, labelSelector, fieldSelector string) (<-chan WatchEvent[T], error) {
+	log.Infof("Namespace: %s, LabelSelector: %s, FieldSelector: %s, Watch %s!", namespace, labelSelector, fieldSelector, c.Kind.GroupVersionKind.Kind)
+	listOptions := metav1.ListOptions{LabelSelector: labelSelector, FieldSelector: fieldSelector}
+	// 第一次查询失败时直接返回错误，例如没有权限
+	events, resourceVersion, known, err := c.relist(ctx, namespace, listOptions, map[cache.ObjectName]string{})
+	if err != nil {
+		return nil, kerrors.Classify(err)
+	}
+
+	ch := make(chan WatchEvent[T])
+	go func() {
+		defer close(ch)
+		send := func(event WatchEvent[T]) bool {
+			select {
+			case ch <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		for _, event := range events {
+			if !send(event) {
+				return
+			}
+		}
+		relist := false
+		for ctx.Err() == nil {
+			var err error
+			if relist {
+				log.Warnf("Namespace: %s, ResourceVersion: %s, Relist %s!", namespace, resourceVersion, c.Kind.GroupVersionKind.Kind)
+				var relistKnown map[cache.ObjectName]string
+				var relistResourceVersion string
+				events, relistResourceVersion, relistKnown, err = c.relist(ctx, namespace, listOptions, known)
+				// 失败时resourceVersion和known保持不变，下一次循环继续重新查询
+				if err == nil {
+					relist = false
+					resourceVersion, known = relistResourceVersion, relistKnown
+					for _, event := range events {
+						if !send(event) {
+							return
+						}
+					}
+				}
+			}
+			if err == nil {
+				watchOptions := listOptions
+				watchOptions.ResourceVersion = resourceVersion
+				watchOptions.AllowWatchBookmarks = true
+				var w watch.Interface
+				w, err = c.watchClient(namespace).Watch(ctx, watchOptions)
+				if err == nil {
+					resourceVersion, err = c.consumeWatch(ctx, w, resourceVersion, known, send)
+				}
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			// 重新查询本身失败时按普通错误处理，等待后再重试
+			if !relist && (apierrors.IsResourceExpired(err) || apierrors.IsGone(err)) {
+				relist = true
+				continue
+			}
+			if err == nil {
+				// 正常断开，例如apiserver的watch超时，立即重新连接
+				continue
+			}
+			if !send(WatchEvent[T]{Type: watch.Error, Err: kerrors.Classify(err)}) {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(watchRetryInterval):
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// consumeWatch 读取watch的事件直到断开，返回最后的resourceVersion，apiserver返回错误事件时返回对应的错误
+func (c *Resource[T, L]) consumeWatch(ctx context.Context, w watch.Interface, resourceVersion string, known map[cache.ObjectName]string, send func(WatchEvent[T]) bool) (string, error) {
+	defer w.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return resourceVersion, ctx.Err()
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return resourceVersion, nil
+			}
+			if event.Type == watch.Error {
+				return resourceVersion, apierrors.FromObject(event.Object)
+			}
+			accessor, err := meta.Accessor(event.Object)
+			if err != nil {
+				return resourceVersion, err
+			}
+			resourceVersion = accessor.GetResourceVersion()
+			if event.Type == watch.Bookmark {
+				continue
+			}
+			item, ok := any(event.Object).(*T)
+			if !ok {
+				return resourceVersion, fmt.Errorf("%s的watch事件中包含未知类型%T", c.Kind.GroupVersionKind.Kind, event.Object)
+			}
+			key := cache.MetaObjectToName(accessor)
+			if event.Type == watch.Deleted {
+				delete(known, key)
+			} else {
+				known[key] = accessor.GetResourceVersion()
+			}
+			c.setTypeMeta(item)
+			if !send(WatchEvent[T]{Type: event.Type, Object: item}) {
+				return resourceVersion, ctx.Err()
+			}
+		}
+	}
+}
+
+// relist 分页查询全部资源，和known对比生成事件，返回列表的resourceVersion和新的known，不会修改传入的known，
+// 查询失败时调用方可以继续使用之前的状态
+func (c *Resource[T, L]) relist(ctx context.Context, namespace string, listOptions metav1.ListOptions, known map[cache.ObjectName]string) ([]WatchEvent[T], string, map[cache.ObjectName]string, error) {
+	listOptions.Limit = DefaultPageSize
+	var events []WatchEvent[T]
+	var resourceVersion string
+	current := map[cache.ObjectName]string{}
+	err := c.listPages(ctx, namespace, listOptions, func(page []T, pageResourceVersion string) bool {
+		// 分页查询时每一页都属于同一个快照，使用第一页的resourceVersion
+		if resourceVersion == "" {
+			resourceVersion = pageResourceVersion
+		}
+		for i := range page {
+			item := &page[i]
+			accessor := any(item).(metav1.Object)
+			key := cache.MetaObjectToName(accessor)
+			current[key] = accessor.GetResourceVersion()
+			previous, ok := known[key]
+			switch {
+			case !ok:
+				events = append(events, WatchEvent[T]{Type: watch.Added, Object: item})
+			case previous != accessor.GetResourceVersion():
+				events = append(events, WatchEvent[T]{Type: watch.Modified, Object: item})
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, "", nil, err
+	}
+	for key := range known {
+		if _, ok := current[key]; ok {
+			continue
+		}
+		item := new(T)
+		accessor := any(item).(metav1.Object)
+		accessor.SetNamespace(key.Namespace)
+		accessor.SetName(key.Name)
+		c.setTypeMeta(item)
+		events = append(events, WatchEvent[T]{Type: watch.Deleted, Object: item})
+	}
+	return events, resourceVersion, current, nil
+}
+
+// EventHandlers Inform的回调函数，为nil的回调会被忽略，回调中的对象是缓存的副本，可以修改
+type EventHandlers[T any] struct {
+	OnAdd    func(obj *T)
+	OnUpdate func(oldObj, newObj *T)
+	OnDelete func(obj *T)
+}
+
+// Inform 基于SharedIndexInformer监听资源的变化并调用handlers，缓存同步完成后返回，ctx取消后停止。
+// 返回的informer可以继续添加回调，或者通过GetIndexer()从本地缓存查询，resync为0时不定期重新同步。
+// ctx在同步完成前被取消时返回context.Canceled，超时时返回的错误满足errors.Is(err, ErrTimeout)
+func (c *Resource[T, L]) Inform(ctx context.Context, namespace, labelSelector, fieldSelector string, resync time.Duration, handlers EventHandlers[T]) (cache.SharedIndexInformer, error) {
+	log.Infof("Namespace: %s, LabelSelector: %s, FieldSelector: %s, Inform %s!", namespace, labelSelector, fieldSelector, c.Kind.GroupVersionKind.Kind)
+	client := c.watchClient(namespace)
+	listWatch := &cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = labelSelector
+			options.FieldSelector = fieldSelector
+			list, err := client.List(ctx, options)
+			if err != nil {
+				return nil, err
+			}
+			return any(list).(runtime.Object), nil
+		},
+		WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = labelSelector
+			options.FieldSelector = fieldSelector
+			return client.Watch(ctx, options)
+		},
+	}
+	informer := cache.NewSharedIndexInformer(listWatch, any(new(T)).(runtime.Object), resync, cache.Indexers{
+		cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+	})
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if item := c.informerItem(obj); item != nil && handlers.OnAdd != nil {
+				handlers.OnAdd(item)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldItem, newItem := c.informerItem(oldObj), c.informerItem(newObj)
+			if oldItem != nil && newItem != nil && handlers.OnUpdate != nil {
+				handlers.OnUpdate(oldItem, newItem)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			// 错过删除事件时，obj为DeletedFinalStateUnknown，其中是最后一次缓存的对象
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if item := c.informerItem(obj); item != nil && handlers.OnDelete != nil {
+				handlers.OnDelete(item)
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	go informer.RunWithContext(ctx)
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return nil, ctx.Err()
+		}
+		return nil, kerrors.New(kerrors.ErrTimeout, fmt.Errorf("%s缓存同步失败: %w", c.Kind.GroupVersionKind.Kind, ctx.Err()))
+	}
+	return informer, nil
+}
+
+// informerItem 返回informer缓存中对象的副本并填充TypeMeta，缓存中的对象是共享的，不能直接修改
+func (c *Resource[T, L]) informerItem(obj interface{}) *T {
+	item, ok := obj.(*T)
+	if !ok {
+		return nil
+	}
+	item = c.deepCopy(item)
+	c.setTypeMeta(item)
+	return item
+}
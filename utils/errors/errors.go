@@ -0,0 +1,176 @@
+/*
+ * @Time : 2026/10/17 23:30
+ * @Author : diehao.yuan
+ * @Email : diehao.yuan@outlook.com
+ * @File : errors.go
+ */
+package errors
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// 错误类型，可以通过errors.Is判断，例如errors.Is(err, ErrNotFound)，原始错误可以通过errors.As获取
+var (
+	// 资源不存在
+	ErrNotFound = errors.New("资源不存在")
+	// 资源已经存在
+	ErrAlreadyExists = errors.New("资源已存在")
+	// resourceVersion冲突或者server-side apply的字段冲突
+	ErrConflict = errors.New("资源冲突")
+	// 没有权限
+	ErrForbidden = errors.New("没有权限")
+	// 认证失败
+	ErrUnauthorized = errors.New("认证失败")
+	// 请求超时，包括apiserver返回的超时和ctx超时
+	ErrTimeout = errors.New("请求超时")
+	// kubeconfig格式错误、缺少字段或者证书无法解析
+	ErrInvalidKubeconfig = errors.New("kubeconfig解析失败")
+	// yaml或json无法解析、资源类型无法识别，或者被apiserver校验为不合法
+	ErrInvalidManifest = errors.New("资源定义不合法")
+	// exec或auth-provider认证插件无法初始化或获取凭证失败
+	ErrAuthProvider = errors.New("认证插件失败")
+	// 无法连接到apiserver，例如DNS解析失败、连接被拒绝或者超时
+	ErrConnection = errors.New("连接集群失败")
+)
+
+// Error 带有错误类型的错误，Reason为上面定义的错误类型之一，Err为原始错误
+type Error struct {
+	Reason error
+	Err    error
+}
+
+func (e *Error) Error() string {
+	return e.Reason.Error() + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Reason
+}
+
+// New 生成指定类型的错误，err为nil时返回nil
+func New(reason, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Reason: reason, Err: err}
+}
+
+// Classify 根据原始错误判断错误类型并包装，无法判断类型或者已经分类过的错误原样返回，err为nil时返回nil
+func Classify(err error) error {
+	if err == nil || Reason(err) != nil {
+		return err
+	}
+	if reason := reasonOf(err); reason != nil {
+		return &Error{Reason: reason, Err: err}
+	}
+	return err
+}
+
+// reasonOf 判断apiserver返回的错误、ctx超时和网络错误的类型
+func reasonOf(err error) error {
+	switch {
+	case apierrors.IsNotFound(err):
+		return ErrNotFound
+	case apierrors.IsAlreadyExists(err):
+		return ErrAlreadyExists
+	case apierrors.IsConflict(err):
+		return ErrConflict
+	case apierrors.IsForbidden(err):
+		return ErrForbidden
+	case apierrors.IsUnauthorized(err):
+		return ErrUnauthorized
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), errors.Is(err, context.DeadlineExceeded):
+		return ErrTimeout
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return ErrInvalidManifest
+	case meta.IsNoMatchError(err):
+		// RESTMapper无法解析的资源类型，通常是kind或apiVersion写错，或者CRD尚未安装
+		return ErrInvalidManifest
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrTimeout
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return ErrConnection
+	}
+	return nil
+}
+
+// Reason 返回错误的类型，不是Error时返回nil
+func Reason(err error) error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Reason
+	}
+	return nil
+}
+
+// 错误类型对应的http状态码，多个错误汇总时按顺序匹配
+var httpStatus = []struct {
+	reason error
+	status int
+}{
+	{ErrNotFound, http.StatusNotFound},
+	{ErrAlreadyExists, http.StatusConflict},
+	{ErrConflict, http.StatusConflict},
+	{ErrForbidden, http.StatusForbidden},
+	{ErrUnauthorized, http.StatusUnauthorized},
+	{ErrAuthProvider, http.StatusUnauthorized},
+	{ErrInvalidKubeconfig, http.StatusBadRequest},
+	{ErrInvalidManifest, http.StatusUnprocessableEntity},
+	{ErrTimeout, http.StatusGatewayTimeout},
+	{ErrConnection, http.StatusBadGateway},
+}
+
+// HTTPStatus 返回错误对应的http状态码，err为nil时返回200，无法分类的错误返回500
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	err = Classify(err)
+	for _, item := range httpStatus {
+		if errors.Is(err, item.reason) {
+			return item.status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// joinError 多个错误的汇总，Error()只返回msg，errors.Is和errors.As会检查其中的每个错误
+type joinError struct {
+	msg  string
+	errs []error
+}
+
+func (e *joinError) Error() string {
+	return e.msg
+}
+
+func (e *joinError) Unwrap() []error {
+	return e.errs
+}
+
+// Join 汇总多个错误，错误信息为msg，errs中的错误会先经过Classify
+func Join(msg string, errs ...error) error {
+	classified := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			classified = append(classified, Classify(err))
+		}
+	}
+	return &joinError{msg: msg, errs: classified}
+}